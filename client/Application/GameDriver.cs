@@ -17,9 +17,27 @@ public class GameDriver : IGameDriver
     }
     public string GetCurrentPlayerUsername()
     { return _playerUsername; }
-    public void SetPlayerUsername(string username)
+    public AuthResponse Register(string username, string password)
     {
-        _playerUsername = username;
+        return Authorize("REGISTER", username, password);
+    }
+    public AuthResponse Login(string username, string password)
+    {
+        return Authorize("LOGIN", username, password);
+    }
+    // Авторизует игровое соединение и подписывается на уведомления токеном сессии
+    private AuthResponse Authorize(string command, string username, string password)
+    {
+        var request = new AuthRequest
+        {
+            Username = username,
+            Password = password
+        };
+
+        var response = SendMessage<AuthResponse>(command, request);
+        _messageController.Subscribe(response.SessionToken);
+        _playerUsername = response.Username;
+        return response;
     }
     public Stream GetRoomStream()
     {
@@ -39,7 +57,6 @@ public class GameDriver : IGameDriver
     {
         var request = new CreateRoomRequest
         {
-            PlayerUsername = _playerUsername,
             RoomID = roomId,
             Password = password,
             Category = category,
@@ -58,7 +75,6 @@ public class GameDriver : IGameDriver
     {
         var request = new UpdateRoomRequest
         {
-            PlayerUsername = _playerUsername,
             RoomID = roomId,
             Password = password,
             Category = category,
@@ -72,7 +88,6 @@ public class GameDriver : IGameDriver
     {
         var request = new StartGameRequest
         {
-            PlayerUsername = _playerUsername,
             RoomID = roomId,
             Password = password,
         };
@@ -84,7 +99,6 @@ public class GameDriver : IGameDriver
     {
         var request = new JoinRoomRequest
         {
-            PlayerUsername = _playerUsername,
             RoomID = roomId,
             Password = password
         };
@@ -95,7 +109,6 @@ public class GameDriver : IGameDriver
     {
         var request = new LeaveRoomRequest
         {
-            PlayerUsername = _playerUsername,
             RoomID = roomId,
             Password = password
         };
@@ -106,7 +119,6 @@ public class GameDriver : IGameDriver
     {
         var request = new DeleteRoomRequest
         {
-            PlayerUsername = _playerUsername,
             RoomID = roomId,
             Password = password
         };
@@ -118,7 +130,6 @@ public class GameDriver : IGameDriver
     {
         var request = new GuessLetterRequest
         {
-            PlayerUsername = _playerUsername,
             RoomID = roomId,
             Password = password,
             Letter = letter.ToString()
@@ -131,7 +142,6 @@ public class GameDriver : IGameDriver
     {
         var request = new GetGameStateRequest
         {
-            PlayerUsername = _playerUsername,
             RoomID = roomId
         };
 
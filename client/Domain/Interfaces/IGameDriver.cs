@@ -8,6 +8,8 @@ namespace client.Domain.Interfaces
     {
         string GetCurrentPlayerUsername();
         CheckUsernameResponse CheckUsername(string username);
+        AuthResponse Register(string username, string password);
+        AuthResponse Login(string username, string password);
         CreateRoomResponse CreateRoom(string roomId, string password, string category, string difficulty);
         UpdateRoomResponse UpdateRoom(
             string roomId,
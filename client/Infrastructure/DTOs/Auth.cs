@@ -0,0 +1,27 @@
+using System.Text.Json.Serialization;
+// Регистрация и вход (REGISTER / LOGIN)
+public class AuthRequest
+{
+    [JsonPropertyName("username")]
+    public required string Username { get; set; }
+
+    [JsonPropertyName("password")]
+    public required string Password { get; set; }
+}
+
+public class AuthResponse
+{
+    [JsonPropertyName("username")]
+    public required string Username { get; set; }
+
+    // Токен сессии для подписки на уведомления
+    [JsonPropertyName("session_token")]
+    public required string SessionToken { get; set; }
+}
+
+// Подписка на уведомления (SUBSCRIBE)
+public class SubscribeRequest
+{
+    [JsonPropertyName("session_token")]
+    public required string SessionToken { get; set; }
+}
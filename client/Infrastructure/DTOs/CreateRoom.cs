@@ -1,8 +1,6 @@
 // Создание команты
 public class CreateRoomRequest
 {
-    // [JsonPropertyName("player_username")]
-    public required string PlayerUsername { get; set; }
     // [JsonPropertyName("room_id")]
     public required string RoomID { get; set; }
     // [JsonPropertyName("password")]
@@ -1,9 +1,6 @@
 // Удаление комнаты
 public class DeleteRoomRequest
 {
-    // [JsonPropertyName("player_username")]
-    public required string PlayerUsername { get; set; }
-
     // [JsonPropertyName("room_id")]
     public required string RoomID { get; set; }
     // [JsonPropertyName("password")]
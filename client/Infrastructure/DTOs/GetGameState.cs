@@ -2,9 +2,6 @@ using System.Text.Json.Serialization;
 // DTO для получения состояния игры
 public class GetGameStateRequest
 {
-    // [JsonPropertyName("player_username")]
-    public required string PlayerUsername { get; set; }
-
     // [JsonPropertyName("room_id")]
     public required string RoomID { get; set; }
 }
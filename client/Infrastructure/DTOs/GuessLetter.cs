@@ -1,9 +1,6 @@
 // DTO для угадывания буквы
 public class GuessLetterRequest
 {
-    // [JsonPropertyName("player_username")]
-    public required string PlayerUsername { get; set; }
-
     // [JsonPropertyName("room_id")]
     public required string RoomID { get; set; }
 
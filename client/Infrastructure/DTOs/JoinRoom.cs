@@ -3,9 +3,6 @@ using System.Text.Json.Serialization;
 // Вход в комнату
 public class JoinRoomRequest
 {
-    // [JsonPropertyName("player_username")]
-    public required string PlayerUsername { get; set; }
-
     // [JsonPropertyName("room_id")]
     public required string RoomID { get; set; }
 
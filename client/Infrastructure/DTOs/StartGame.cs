@@ -1,8 +1,6 @@
 // Старт игры
 public class StartGameRequest
 {
-    // [JsonPropertyName("player_username")]
-    public required string PlayerUsername { get; set; }
     // [JsonPropertyName("room_id")]
     public required string RoomID { get; set; }
     // [JsonPropertyName("password")]
@@ -45,67 +45,33 @@ public class MessageController
             throw;
         }
     }
-    public GameEvent? TryToGetServerEvent(CancellationToken cancellationToken)
+    /// <summary>
+    /// Подписывается на уведомления токеном сессии и начинает принимать события.
+    /// </summary>
+    public void Subscribe(string sessionToken)
     {
-        while (true)
+        var request = new SubscribeRequest
         {
-            cancellationToken.ThrowIfCancellationRequested(); // Проверяем токен отмены
-
-            try
-            {
-                // Читаем сообщение из потока
-                // var stream = _clientHandler.GetStream();
-                var serverResponse = _clientHandler.ReadNotification<ServerResponse>();
-                // _logger.Debug(serverResponse.ToString());
-                if (serverResponse == null)
-                {
-                    _logger.Warn("No response received from server.");
-                    continue; // Пропускаем итерацию, если ответ пуст
-                }
-
-                if (serverResponse.Payload == null || serverResponse.Payload.IsEmpty)
-                {
-                    // Console.WriteLine("Received empty payload from server.");
-                    continue; // Пропускаем итерацию, если payload пустой
-                }
-
-                // Обработка типа сообщения
-                switch (serverResponse.Message)
-                {
-                    case "GameStarted":
-                        return _clientHandler.DeserializePayload<GameStartedEvent>(serverResponse.Payload);
-
-                    case "PlayerJoined":
-                        return _clientHandler.DeserializePayload<PlayerJoinedEvent>(serverResponse.Payload);
-
-                    case "PlayerLeft":
-                        return _clientHandler.DeserializePayload<PlayerLeftEvent>(serverResponse.Payload);
-
-                    default:
-                        throw new InvalidOperationException($"Unknown event type: {serverResponse.Message}");
-                }
-            }
-            catch (OperationCanceledException)
-            {
-                _logger.Info("Operation was canceled.");
-                throw; // Пробрасываем исключение отмены
-            }
-            catch (Exception ex)
-            {
-                _logger.Error($"Unexpected error during server communication: {ex.Message}");
-                // Проверяем, был ли запрошен токен отмены, и пробрасываем исключение отмены
-                // throw new OperationCanceledException(cancellationToken);
-                throw; // Пробрасываем другие исключения
-            }
+            SessionToken = sessionToken
+        };
+        var response = _clientHandler.Subscribe(_clientHandler.SerializeToJson(request));
+        if (response.StatusCode != statusSuccess)
+        {
+            throw new Exception($"Server returned error: {response.StatusCode} {response.Message}");
         }
+        _clientHandler.ListenNotifications();
+    }
+    public GameEvent? TryToGetServerEvent(CancellationToken cancellationToken)
+    {
+        return TryToGetServerEventAsync(cancellationToken).GetAwaiter().GetResult();
     }
     /// <summary>
-    /// Обрабатывает событие "GameStarted" из ServerResponse.
+    /// Ожидает следующее событие, которое клиент умеет показывать.
+    /// Остальные события сервера (ходы, таймеры, итоги раундов) пропускаются.
     /// </summary>
-    /// <param name="serverResponse">Ответ сервера в формате protobuf.</param>
-    /// <returns>Объект GameStartedEvent.</returns>
     public async Task<GameEvent?> TryToGetServerEventAsync(CancellationToken cancellationToken)
     {
+        while (true)
         {
             cancellationToken.ThrowIfCancellationRequested(); // Проверяем токен отмены
 
@@ -139,7 +105,8 @@ public class MessageController
                         return _clientHandler.DeserializePayload<RoomDeletedEvent>(serverResponse.Payload);
 
                     default:
-                        throw new InvalidOperationException($"Unknown event type: {serverResponse.Message}");
+                        _logger.Debug($"Skipping event: {serverResponse.Message}");
+                        continue;
                 }
             }
             catch (OperationCanceledException)
@@ -154,4 +121,4 @@ public class MessageController
             }
         }
     }
-}
\ No newline at end of file
+}
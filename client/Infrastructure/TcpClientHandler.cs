@@ -3,6 +3,7 @@ using System.IO;
 using System.Net.Sockets;
 using System.Text;
 using System.Text.Json;
+using System.Threading.Channels;
 using Google.Protobuf;
 using Tcp;
 using NLog;
@@ -19,6 +20,8 @@ namespace client.Infrastructure
         private TcpClient? _notificationClient;
         private NetworkStream? _gameStream;
         private NetworkStream? _notificationStream;
+        // События, прочитанные из соединения уведомлений
+        private readonly Channel<ServerResponse> _notifications = Channel.CreateUnbounded<ServerResponse>();
 
         public TcpClientHandler(string address, int gamePort, int notificationPort)
         {
@@ -58,19 +61,6 @@ namespace client.Infrastructure
             }
         }
 
-        public async Task ReconnectToNotificationServerAsync()
-        {
-            if (_notificationClient != null)
-            {
-                _notificationClient.Close();
-            }
-
-            _notificationClient = new TcpClient();
-            await _notificationClient.ConnectAsync(_address, _notificationPort);
-            _notificationStream = _notificationClient.GetStream();
-            Logger.Info("Reconnected to notification server.");
-        }
-
         /// <summary>
         /// Сериализует объект в JSON-строку.
         /// </summary>
@@ -122,30 +112,7 @@ namespace client.Infrastructure
                     //     // return default;;
                     //     Reconnect();
                     // }
-                    var clientMessage = new ClientMessage
-                    {
-                        Command = command,
-                        Payload = ByteString.CopyFromUtf8(payload)
-                    };
-
-                    // Подготовка данных
-                    byte[] data = clientMessage.ToByteArray();
-                    byte[] header = BitConverter.GetBytes(data.Length);
-
-                    // Приведение к big-endian, если необходимо
-                    if (BitConverter.IsLittleEndian)
-                        Array.Reverse(header);
-
-                    // Отправка данных
-                    if (_gameStream != null)
-                    {
-                        _gameStream.Write(header, 0, header.Length);
-                        _gameStream.Write(data, 0, data.Length);
-                    }
-                    else
-                    {
-                        throw new InvalidOperationException("Network stream is not initialized.");
-                    }
+                    WriteMessage(_gameStream, command, payload);
 
                     Logger.Info($"Sent command: {command}, Payload: {payload}");
                     return; // Успешная отправка, выходим из метода
@@ -167,6 +134,74 @@ namespace client.Infrastructure
         }
 
 
+        /// <summary>
+        /// Записывает ClientMessage в поток с префиксом длины.
+        /// </summary>
+        private static void WriteMessage(NetworkStream? stream, string command, string payload)
+        {
+            if (stream == null)
+            {
+                throw new InvalidOperationException("Network stream is not initialized.");
+            }
+
+            var clientMessage = new ClientMessage
+            {
+                Command = command,
+                Payload = ByteString.CopyFromUtf8(payload)
+            };
+
+            // Подготовка данных
+            byte[] data = clientMessage.ToByteArray();
+            byte[] header = BitConverter.GetBytes(data.Length);
+
+            // Приведение к big-endian, если необходимо
+            if (BitConverter.IsLittleEndian)
+                Array.Reverse(header);
+
+            stream.Write(header, 0, header.Length);
+            stream.Write(data, 0, data.Length);
+        }
+
+        /// <summary>
+        /// Отправляет SUBSCRIBE первым сообщением в соединение уведомлений и возвращает ответ сервера.
+        /// </summary>
+        public ServerResponse Subscribe(string payload)
+        {
+            WriteMessage(_notificationStream, "SUBSCRIBE", payload);
+            Logger.Info("Sent command: SUBSCRIBE");
+            return ReadNotification<ServerResponse>();
+        }
+
+        /// <summary>
+        /// Запускает фоновое чтение событий из соединения уведомлений.
+        /// Одно чтение на всё соединение не теряет события между комнатами и при отмене ожидания.
+        /// </summary>
+        public void ListenNotifications()
+        {
+            Task.Run(() =>
+            {
+                try
+                {
+                    while (true)
+                    {
+                        var notification = ReadNotification<ServerResponse>();
+                        // Пустое сообщение означает, что сервер закрыл соединение
+                        if (notification.StatusCode == 0)
+                        {
+                            Logger.Warn("Notification connection closed.");
+                            _notifications.Writer.TryComplete();
+                            return;
+                        }
+                        _notifications.Writer.TryWrite(notification);
+                    }
+                }
+                catch (Exception ex)
+                {
+                    _notifications.Writer.TryComplete(ex);
+                }
+            });
+        }
+
         /// <summary>
         /// Получает сообщение от сервера и десериализует его.
         /// </summary>
@@ -294,48 +329,15 @@ namespace client.Infrastructure
                 throw;
             }
         }
+        /// <summary>
+        /// Ожидает следующее событие из соединения уведомлений.
+        /// Отмена прерывает только ожидание: подписка и непрочитанные события сохраняются.
+        /// </summary>
         public async Task<ServerResponse> ReadMessageFromStreamAsync(CancellationToken cancellationToken)
         {
             try
             {
-                await ReconnectToNotificationServerAsync();
-                var stream = _notificationClient?.GetStream() ?? throw new InvalidOperationException("Notification client is not initialized.");
-
-                if (stream == null || !stream.CanRead)
-                {
-                    throw new InvalidOperationException("NetworkStream is not available for reading.");
-                }
-                // Читаем префикс длины (4 байта)
-                byte[] header = new byte[4];
-                int headerRead = await ReadWithCancellationAsync(stream, header, 0, header.Length, cancellationToken);
-                if (headerRead != header.Length)
-                {
-                    throw new Exception("Failed to read message length.");
-                }
-
-                // Преобразуем заголовок в длину сообщения
-                if (BitConverter.IsLittleEndian)
-                {
-                    Array.Reverse(header);
-                }
-                int messageLength = BitConverter.ToInt32(header, 0);
-
-                // Читаем тело сообщения
-                byte[] body = new byte[messageLength];
-                int bytesRead = 0;
-                while (bytesRead < messageLength)
-                {
-                    int chunkRead = await ReadWithCancellationAsync(stream, body, bytesRead, messageLength - bytesRead, cancellationToken);
-                    if (chunkRead == 0 && cancellationToken.IsCancellationRequested)
-                    {
-                        // Отмена чтения, но соединение остаётся открытым
-                        throw new OperationCanceledException("Read operation canceled.");
-                    }
-                    bytesRead += chunkRead;
-                }
-
-                // Десериализация
-                return ServerResponse.Parser.ParseFrom(body);
+                return await _notifications.Reader.ReadAsync(cancellationToken);
             }
             catch (OperationCanceledException)
             {
@@ -348,35 +350,6 @@ namespace client.Infrastructure
             }
         }
 
-        private async Task<int> ReadWithCancellationAsync(NetworkStream stream, byte[] buffer, int offset, int count, CancellationToken cancellationToken)
-        {
-            using var linkedCts = CancellationTokenSource.CreateLinkedTokenSource(cancellationToken);
-            var readTask = stream.ReadAsync(buffer, offset, count, linkedCts.Token);
-            try
-            {
-                // Ждём завершения чтения или отмены
-                var completedTask = await Task.WhenAny(readTask, Task.Delay(Timeout.Infinite, cancellationToken));
-                if (completedTask == readTask)
-                {
-                    return await readTask; // Успешное чтение
-                }
-
-                // Если задача отменена, выбрасываем исключение
-                linkedCts.Cancel(); // Сигнализируем об отмене
-                throw new OperationCanceledException("Operation canceled by token.");
-            }
-            catch (OperationCanceledException)
-            {
-                // В случае отмены возвращаем 0 байт для корректного поведения цикла
-                return 0;
-            }
-            catch (Exception)
-            {
-                // Пробрасываем исключения, кроме отмены
-                throw;
-            }
-        }
-
         /// <summary>
         /// Реализация IDisposable для автоматического освобождения ресурсов.
         /// </summary>
@@ -386,6 +359,8 @@ namespace client.Infrastructure
             {
                 _gameStream?.Close();
                 _gameClient?.Close();
+                _notificationStream?.Close();
+                _notificationClient?.Close();
 
                 Logger.Info("Connections closed.");
             }
@@ -35,6 +35,27 @@ namespace client.Menu
         {
             _gameDriver = driver;
         }
+        // Запрашивает имя и пароль и авторизует игрока: новое имя регистрируется, занятое — входит в учётную запись
+        public string GetAuthorizedUsername()
+        {
+            while (true)
+            {
+                string username = GetValidatedUsername();
+                bool isNew = _gameDriver.CheckUsername(username).IsUnique;
+                string password = GetPassword(isNew ? "Choose a password" : "Enter your password");
+                try
+                {
+                    var response = isNew
+                        ? _gameDriver.Register(username, password)
+                        : _gameDriver.Login(username, password);
+                    return response.Username;
+                }
+                catch (Exception ex)
+                {
+                    Console.WriteLine($"Failed to {(isNew ? "register" : "log in")}: {ex.Message}");
+                }
+            }
+        }
         public string GetValidatedUsername()
         {
             string username;
@@ -46,13 +67,6 @@ namespace client.Menu
                 // Проверка: от 3 до 15 символов, только буквы и цифры
                 if (!string.IsNullOrEmpty(username) && Regex.IsMatch(username, @"^[a-zA-Z0-9]{3,15}$"))
                 {
-                    // Проверка уникальности имени пользователя
-                    var response = _gameDriver.CheckUsername(username);
-                    if (!response.IsUnique)
-                    {
-                        Console.WriteLine("Username is already taken. Please choose another one.");
-                        continue;
-                    }
                     break;
                 }
 
@@ -60,5 +74,18 @@ namespace client.Menu
             }
             return username;
         }
+        private static string GetPassword(string prompt)
+        {
+            string? password = null;
+            while (string.IsNullOrWhiteSpace(password))
+            {
+                Console.Write($"{prompt}: ");
+                password = Console.ReadLine();
+
+                if (string.IsNullOrWhiteSpace(password))
+                    Console.WriteLine("Password cannot be empty. Please try again.");
+            }
+            return password;
+        }
     }
 }
\ No newline at end of file
@@ -64,8 +64,8 @@ namespace client
 
             var gameDriver = new GameDriver(messageController);
             var usernameVlaidaor = new UsernameValidator(gameDriver);
-            string username = usernameVlaidaor.GetValidatedUsername();
-            gameDriver.SetPlayerUsername(username);
+            // Вход или регистрация: сессия авторизует игровое соединение и подписку на уведомления
+            usernameVlaidaor.GetAuthorizedUsername();
             var gameUi = new GameUI(gameDriver);
 
             string headerText = "  _   _                                         " +
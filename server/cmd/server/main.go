@@ -9,6 +9,9 @@ import (
 	"hangman/pkg/tcp-server"
 	"hangman/pkg/utils"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 )
 
@@ -17,18 +20,26 @@ func main() {
 	logger := utils.NewCustomLogger(utils.LevelDebug)
 	// Репозитории
 	roomRepo := repository.NewRoomRepository()
-	wordsRepo, err := repository.NewWordsRepository("../assets" + string(os.PathSeparator) + "words.json")
+	// Все наборы слов (JSON, CSV, TXT) из каталога ассетов
+	wordsRepo, err := repository.NewWordsRepository("../assets")
 	if err != nil {
 		logger.Fatal(fmt.Sprintf("Failed to init words repo: %v", err))
 	}
-	playerRepo := repository.NewPlayerRepository()
+	playerRepo, err := repository.NewBoltPlayerRepository("hangman.db")
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Failed to open player store: %v", err))
+	}
+	defer playerRepo.Close()
 	ctxRepo := ctx_repo.NewCtxRepository()
+	snapshotRepo := repository.NewSnapshotRepository("snapshot.json")
 	// Сервисы
 	gameService := service.NewGameService(wordsRepo)
 	roomController := service.NewRoomController(roomRepo, playerRepo, gameService, ctxRepo)
+	authService := service.NewAuthService(playerRepo)
+	snapshotService := service.NewSnapshotService(roomRepo, snapshotRepo)
 
 	// Обработчики
-	handler := tcp.NewHandler(roomController)
+	handler := tcp.NewHandler(roomController, authService)
 
 	// Канал для передачи неактивных игроков
 	inactivePlayersChan := make(chan []string)
@@ -59,7 +70,99 @@ func main() {
 
 	// Создаём и запускаем TCP-сервер
 	srv := tcp_server.New(":8001", ctxRepo, logger) // Передаем RoomController и Logger
+	// TimerTick приходит каждую секунду и не должен забивать журнал
+	srv.NotificationServer().SetDebugEvents("TimerTick")
+	// Лимиты запросов: общий на соединение и игрока, строже — для дорогих команд и подбора пароля
+	rateLimiter := tcp_server.NewRateLimiter(tcp_server.RateLimitConfig{
+		PerConnection: tcp_server.RateLimit{Rate: 20, Burst: 40},
+		PerPlayer:     tcp_server.RateLimit{Rate: 30, Burst: 60},
+		PerCommand: map[string]tcp_server.RateLimit{
+			"CREATE_ROOM":  {Rate: 0.2, Burst: 3},
+			"GUESS_LETTER": {Rate: 5, Burst: 10},
+			"GUESS_WORD":   {Rate: 1, Burst: 3},
+			"REQUEST_HINT": {Rate: 1, Burst: 3},
+			"REGISTER":     {Rate: 0.2, Burst: 3},
+			"LOGIN":        {Rate: 0.5, Burst: 5},
+		},
+	})
+	// Время ограничено только у команд чтения: прерванный ответ на них ничего не меняет
+	readTimeouts := map[string]time.Duration{
+		"GET_GAME_STATE":  10 * time.Second,
+		"GET_ROOM_STATE":  10 * time.Second,
+		"GET_ALL_ROOMS":   10 * time.Second,
+		"GET_LEADERBOARD": 10 * time.Second,
+		"CHECK_USERNAME":  10 * time.Second,
+	}
+	srv.Use(
+		tcp_server.Logging(logger),
+		tcp_server.RateLimiting(rateLimiter, logger),
+		tcp_server.Timeout(readTimeouts),
+		tcp_server.Recovery(logger),
+		tcp.TrackActivity(playerRepo),
+	)
 	handler.InitRoutes(srv)
+
+	// Восстанавливаем комнаты и игры, сохранённые до перезапуска
+	restored, err := snapshotService.Restore(srv.NotificationServer())
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to restore snapshot: %v", err))
+	} else {
+		logger.Info(fmt.Sprintf("Restored %d rooms from snapshot", restored))
+	}
+
+	// Периодически сохраняем снимок комнат
+	go func() {
+		interval := 30 * time.Second // Интервал сохранения снимков
+		logger.Info(fmt.Sprintf("Snapshot process started with interval: %v", interval))
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := snapshotService.Save(); err != nil {
+				logger.Error(fmt.Sprintf("Failed to save snapshot: %v", err))
+			}
+		}
+	}()
+
+	// Перечитываем наборы слов при изменении файлов и по SIGHUP, не останавливая игры
+	reloaded := func(err error) {
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to reload words, keeping previous ones: %v", err))
+			return
+		}
+		logger.Info(fmt.Sprintf("Words reloaded: %d categories", len(wordsRepo.GetCategories())))
+	}
+	if err := wordsRepo.Watch(reloaded); err != nil {
+		logger.Error(fmt.Sprintf("Failed to watch word packs: %v", err))
+	}
+	go func() {
+		hangups := make(chan os.Signal, 1)
+		signal.Notify(hangups, syscall.SIGHUP)
+		for range hangups {
+			reloaded(wordsRepo.Reload())
+		}
+	}()
+
+	// Сохраняем снимок перед остановкой сервера
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		sig := <-signals
+		logger.Info(fmt.Sprintf("Received %v, saving snapshot", sig))
+		if err := snapshotService.Save(); err != nil {
+			logger.Error(fmt.Sprintf("Failed to save snapshot: %v", err))
+		}
+		playerRepo.Close()
+		os.Exit(0)
+	}()
+	// WebSocket-шлюз для веб-клиента работает рядом с TCP-сервером.
+	// Страницы с других origin перечисляются через запятую в HANGMAN_WS_ORIGINS
+	gateway := tcp_server.NewWSGateway(":8003", srv, strings.Split(os.Getenv("HANGMAN_WS_ORIGINS"), ","))
+	go func() {
+		if err := gateway.Start(); err != nil {
+			logger.Fatal(fmt.Sprintf("Failed to start WebSocket gateway: %v", err))
+		}
+	}()
+
 	if err := srv.Start(); err != nil {
 		logger.Fatal(fmt.Sprintf("Failed to start server: %v", err))
 	}
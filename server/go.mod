@@ -2,4 +2,12 @@ module hangman
 
 go 1.22.0
 
-require google.golang.org/protobuf v1.35.2
+require (
+	github.com/fsnotify/fsnotify v1.7.0
+	github.com/gorilla/websocket v1.5.3
+	go.etcd.io/bbolt v1.3.11
+	golang.org/x/crypto v0.31.0
+	google.golang.org/protobuf v1.35.2
+)
+
+require golang.org/x/sys v0.28.0 // indirect
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Account — учётная запись игрока.
+type Account struct {
+	Username     string
+	PasswordHash string // bcrypt-хэш; соль хранится внутри него
+	Salt         string // Только у записей со старым хэшем SHA-256
+}
+
+// NewAccount создаёт учётную запись и хэширует пароль bcrypt.
+func NewAccount(username, password string) (*Account, error) {
+	account := &Account{Username: username}
+	if err := account.SetPassword(password); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// SetPassword заменяет хэш пароля. bcrypt учитывает не больше 72 байт пароля,
+// более длинный пароль отклоняется с bcrypt.ErrPasswordTooLong.
+func (a *Account) SetPassword(password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	a.PasswordHash = string(hash)
+	a.Salt = ""
+	return nil
+}
+
+// CheckPassword сверяет пароль с сохранённым хэшем за постоянное время.
+func (a *Account) CheckPassword(password string) bool {
+	if a.IsLegacyHash() {
+		sum := sha256.Sum256([]byte(a.Salt + password))
+		return subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(a.PasswordHash)) == 1
+	}
+	return bcrypt.CompareHashAndPassword([]byte(a.PasswordHash), []byte(password)) == nil
+}
+
+// IsLegacyHash сообщает, что пароль хранится старым быстрым хэшем и его нужно перехэшировать при входе.
+func (a *Account) IsLegacyHash() bool {
+	return a.Salt != ""
+}
+
+// PlayerStats — накопленная статистика игрока за всё время.
+type PlayerStats struct {
+	Username      string
+	Score         int
+	GamesPlayed   int
+	GamesWon      int
+	CurrentStreak int
+	BestStreak    int
+}
+
+// RecordGame учитывает результат завершённой игры.
+func (s *PlayerStats) RecordGame(score int, won bool) {
+	s.Score += score
+	s.GamesPlayed++
+	if !won {
+		s.CurrentStreak = 0
+		return
+	}
+	s.GamesWon++
+	s.CurrentStreak++
+	if s.CurrentStreak > s.BestStreak {
+		s.BestStreak = s.CurrentStreak
+	}
+}
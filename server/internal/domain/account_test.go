@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAccountPassword(t *testing.T) {
+	account, err := NewAccount("alice", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(account.PasswordHash, "$2") || account.IsLegacyHash() {
+		t.Errorf("expected a bcrypt hash, got %q", account.PasswordHash)
+	}
+	if !account.CheckPassword("secret") || account.CheckPassword("Secret") {
+		t.Errorf("password check is wrong")
+	}
+	if _, err := NewAccount("bob", strings.Repeat("x", 73)); err == nil {
+		t.Errorf("passwords longer than bcrypt accepts must be rejected")
+	}
+
+	// Запись со старым хэшем SHA-256 (соль "00", пароль "secret") по-прежнему открывается
+	legacy := &Account{
+		Username:     "carol",
+		Salt:         "00",
+		PasswordHash: "a0e09c1f5aa2633db2bcd07d44a8ff7b566249e7d71b4eed87fc93f8cbdf5cd5",
+	}
+	if !legacy.IsLegacyHash() || !legacy.CheckPassword("secret") || legacy.CheckPassword("wrong") {
+		t.Errorf("legacy hash must still verify")
+	}
+	if err := legacy.SetPassword("secret"); err != nil || legacy.IsLegacyHash() || !legacy.CheckPassword("secret") {
+		t.Errorf("rehash must switch the account to bcrypt, got %+v, %v", legacy, err)
+	}
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ErrLetterNotInAlphabet возвращается, если буква не входит в алфавит категории.
+var ErrLetterNotInAlphabet = errors.New("letter is not in the alphabet")
+
+// DefaultLocale — алфавит категорий, в которых он не указан.
+const DefaultLocale = "ru"
+
+// Буквы поддерживаемых алфавитов в нижнем регистре.
+var alphabetLetters = map[string]string{
+	"ru": "абвгдеёжзийклмнопрстуфхцчшщъыьэюя",
+	"en": "abcdefghijklmnopqrstuvwxyz",
+}
+
+// Alphabet описывает допустимые буквы категории и правила их нормализации.
+type Alphabet struct {
+	Locale  string
+	FoldYo  bool // Считать «ё» и «е» одной буквой
+	letters map[rune]bool
+}
+
+// NewAlphabet создаёт алфавит по коду языка. Пустой код означает DefaultLocale.
+func NewAlphabet(locale string, foldYo bool) (*Alphabet, error) {
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	letters, ok := alphabetLetters[locale]
+	if !ok {
+		return nil, fmt.Errorf("unknown alphabet: %s", locale)
+	}
+	alphabet := &Alphabet{Locale: locale, FoldYo: foldYo, letters: make(map[rune]bool)}
+	for _, r := range letters {
+		alphabet.letters[r] = true
+	}
+	return alphabet, nil
+}
+
+// fold приводит букву к нижнему регистру и при необходимости заменяет «ё» на «е».
+func (a *Alphabet) fold(r rune) rune {
+	r = unicode.ToLower(r)
+	if a.FoldYo && r == 'ё' {
+		return 'е'
+	}
+	return r
+}
+
+// NormalizeLetter готовит букву из запроса к сравнению со словом.
+// Символы вне алфавита отклоняются с ErrLetterNotInAlphabet.
+func (a *Alphabet) NormalizeLetter(letter rune) (rune, error) {
+	letter = a.fold(letter)
+	if !a.letters[letter] {
+		return 0, ErrLetterNotInAlphabet
+	}
+	return letter, nil
+}
+
+// NormalizeWord приводит слово к виду, в котором его хранит игра.
+// Символы вне алфавита (например, дефисы) не меняются.
+func (a *Alphabet) NormalizeWord(word string) string {
+	return strings.Map(a.fold, word)
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAlphabetNormalizeLetter(t *testing.T) {
+	alphabet, err := NewAlphabet("ru", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[rune]rune{'А': 'а', 'ё': 'е', 'Ё': 'е', 'ж': 'ж'}
+	for input, expected := range cases {
+		if got, err := alphabet.NormalizeLetter(input); err != nil || got != expected {
+			t.Errorf("NormalizeLetter(%q) = %q, %v; expected %q", input, got, err, expected)
+		}
+	}
+	for _, input := range []rune{'a', '7', '-', '😀'} {
+		if _, err := alphabet.NormalizeLetter(input); !errors.Is(err, ErrLetterNotInAlphabet) {
+			t.Errorf("NormalizeLetter(%q): expected ErrLetterNotInAlphabet, got %v", input, err)
+		}
+	}
+
+	strict, _ := NewAlphabet("ru", false)
+	if got, _ := strict.NormalizeLetter('Ё'); got != 'ё' {
+		t.Errorf("without folding expected 'ё', got %q", got)
+	}
+	if got := alphabet.NormalizeWord("Ёлка-Палка"); got != "елка-палка" {
+		t.Errorf("NormalizeWord: got %q", got)
+	}
+	if _, err := NewAlphabet("xx", false); err == nil {
+		t.Errorf("expected error for unknown alphabet")
+	}
+}
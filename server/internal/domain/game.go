@@ -1,31 +1,55 @@
 package domain
 
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrLetterAlreadyGuessed возвращается при повторной попытке назвать ту же букву.
+var ErrLetterAlreadyGuessed = errors.New("letter already guessed")
+
 type Game struct {
 	Word         string
 	GuessedWord  []rune
 	AttemptsLeft int
 	Score        int
+	Closed       bool      // Игра закрыта досрочно: соперник первым отгадал слово или истекло время
+	Hint         string    // Текст подсказки из банка слов
+	HintShown    bool      // Текст подсказки уже показан
+	HintsLeft    int       // Остаток подсказок
+	Correct      []rune    // Названные буквы, которые есть в слове
+	Wrong        []rune    // Названные буквы, которых в слове нет
+	TurnStarted  time.Time `json:"-"` // Когда игрок получил ход; после перезапуска часы идут заново
 }
 
 func NewGame(word string, attempts int) *Game {
-	guessedWord := make([]rune, len(word))
-	for i, r := range word {
-		if r == '-' {
-			guessedWord[i] = '-' // Дефисы сразу считаются угаданными
+	letters := []rune(word)
+	guessedWord := make([]rune, len(letters))
+	for i, r := range letters {
+		if isPreRevealed(r) {
+			guessedWord[i] = r // Пробелы, дефисы и прочие знаки сразу считаются угаданными
 		}
 	}
 	return &Game{
 		Word:         word,
-		GuessedWord:  make([]rune, len(word)),
+		GuessedWord:  guessedWord,
 		AttemptsLeft: attempts,
 	}
 }
 
+// isPreRevealed сообщает, что символ слова не нужно отгадывать
+func isPreRevealed(r rune) bool {
+	return !unicode.IsLetter(r)
+}
+
 // Проверка текущего состояния слова
 func (g *Game) DisplayWord() string {
-	displayed := make([]rune, len(g.Word))
-	for i, r := range g.Word {
-		if contains(g.GuessedWord, r) {
+	letters := []rune(g.Word)
+	displayed := make([]rune, len(letters))
+	for i, r := range letters {
+		if g.GuessedWord[i] == r {
 			displayed[i] = r
 		} else {
 			displayed[i] = '_'
@@ -36,26 +60,96 @@ func (g *Game) DisplayWord() string {
 
 // Обновление угаданного слова
 func (g *Game) UpdateGuessedWord(letter rune) bool {
-	found := false
-	for i, r := range g.Word {
+	return len(g.revealLetter(letter)) > 0
+}
+
+// revealLetter открывает все вхождения буквы и возвращает открытые позиции
+func (g *Game) revealLetter(letter rune) []int {
+	var positions []int
+	for i, r := range []rune(g.Word) {
 		if r == letter && g.GuessedWord[i] != letter {
 			g.GuessedWord[i] = letter
-			found = true
+			positions = append(positions, i)
+		}
+	}
+	return positions
+}
+
+// GuessLetter проверяет букву и запоминает её среди верных или неверных.
+// Возвращает открытые позиции; пустой список означает, что буквы в слове нет.
+// Повторно названная буква отклоняется с ErrLetterAlreadyGuessed.
+func (g *Game) GuessLetter(letter rune) ([]int, error) {
+	if contains(g.Correct, letter) || contains(g.Wrong, letter) {
+		return nil, ErrLetterAlreadyGuessed
+	}
+	positions := g.revealLetter(letter)
+	if len(positions) > 0 {
+		g.Correct = append(g.Correct, letter)
+	} else {
+		g.Wrong = append(g.Wrong, letter)
+	}
+	return positions, nil
+}
+
+// GuessWord сравнивает слово целиком без учёта регистра и при совпадении открывает все буквы.
+// Возвращает позиции, открытые угаданным словом.
+func (g *Game) GuessWord(word string) ([]int, bool) {
+	if !strings.EqualFold(strings.TrimSpace(word), g.Word) {
+		return nil, false
+	}
+	var positions []int
+	for i, r := range []rune(g.Word) {
+		if g.GuessedWord[i] != r {
+			g.GuessedWord[i] = r
+			positions = append(positions, i)
 		}
 	}
-	return found
+	return positions, true
+}
+
+// RevealLetter открывает первую неотгаданную букву.
+// Последнюю букву не открывает, чтобы подсказка не завершала игру.
+func (g *Game) RevealLetter() (rune, bool) {
+	var hidden []rune
+	for i, r := range []rune(g.Word) {
+		if g.GuessedWord[i] != r && !contains(hidden, r) {
+			hidden = append(hidden, r)
+		}
+	}
+	if len(hidden) < 2 {
+		return 0, false
+	}
+	g.UpdateGuessedWord(hidden[0])
+	g.Correct = append(g.Correct, hidden[0])
+	return hidden[0], true
 }
 
 // Проверка, угадано ли слово
 func (g *Game) IsWordGuessed() bool {
-	for i, r := range g.Word {
-		if r != '-' && g.GuessedWord[i] != r {
+	for i, r := range []rune(g.Word) {
+		if g.GuessedWord[i] != r {
 			return false
 		}
 	}
 	return true
 }
 
+// IsOver проверяет, завершена ли игра: слово угадано, попытки кончились или игра закрыта
+func (g *Game) IsOver() bool {
+	return g.Closed || g.IsWordGuessed() || g.AttemptsLeft <= 0
+}
+
+// RevealedCount возвращает число открытых букв без учёта знаков, открытых с самого начала
+func (g *Game) RevealedCount() int {
+	count := 0
+	for _, r := range g.GuessedWord {
+		if r != 0 && !isPreRevealed(r) {
+			count++
+		}
+	}
+	return count
+}
+
 // Проверка наличия символа в срезе
 func contains(slice []rune, r rune) bool {
 	for _, v := range slice {
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// GameMode — режим игры в комнате.
+type GameMode string
+
+const (
+	ModeClassic   GameMode = "classic"    // У каждого игрока своё слово
+	ModeTurnBased GameMode = "turn_based" // Одно слово на всех, игроки ходят по очереди
+	ModeRace      GameMode = "race"       // Одно слово на всех, кто первым отгадает — побеждает
+)
+
+// ErrNotYourTurn возвращается при попытке угадать букву вне своей очереди.
+var ErrNotYourTurn = errors.New("not your turn")
+
+// ParseGameMode разбирает режим из запроса. Пустая строка означает классический режим.
+func ParseGameMode(mode string) (GameMode, error) {
+	switch GameMode(mode) {
+	case "", ModeClassic:
+		return ModeClassic, nil
+	case ModeTurnBased, ModeRace:
+		return GameMode(mode), nil
+	}
+	return "", fmt.Errorf("unknown game mode: %s", mode)
+}
@@ -71,3 +71,52 @@ func TestContains(t *testing.T) {
 		t.Errorf("Expected contains to return false for 'z'")
 	}
 }
+
+func TestGuessLetterRepeats(t *testing.T) {
+	game := NewGame("golang", 5)
+
+	if positions, err := game.GuessLetter('g'); len(positions) != 2 || positions[0] != 0 || positions[1] != 5 || err != nil {
+		t.Fatalf("Expected 'g' at positions 0 and 5, got %v, %v", positions, err)
+	}
+	if positions, err := game.GuessLetter('z'); len(positions) != 0 || err != nil {
+		t.Fatalf("Expected 'z' to be missing, got %v, %v", positions, err)
+	}
+	for _, letter := range []rune{'g', 'z'} {
+		if _, err := game.GuessLetter(letter); err != ErrLetterAlreadyGuessed {
+			t.Errorf("Expected ErrLetterAlreadyGuessed for %q, got %v", letter, err)
+		}
+	}
+	if string(game.Correct) != "g" || string(game.Wrong) != "z" {
+		t.Errorf("Expected correct=g wrong=z, got %q and %q", string(game.Correct), string(game.Wrong))
+	}
+}
+
+func TestCyrillicWord(t *testing.T) {
+	game := NewGame("абу-даби", 5)
+
+	if len(game.GuessedWord) != 8 {
+		t.Fatalf("Expected GuessedWord length to be 8, got %d", len(game.GuessedWord))
+	}
+	if display := game.DisplayWord(); display != "___-____" {
+		t.Errorf("Expected hyphen to be revealed, got %s", display)
+	}
+	if !game.UpdateGuessedWord('а') {
+		t.Fatalf("Expected UpdateGuessedWord to return true for letter 'а'")
+	}
+	if display := game.DisplayWord(); display != "а__-_а__" {
+		t.Errorf("Expected DisplayWord to be а__-_а__, got %s", display)
+	}
+	if game.RevealedCount() != 2 {
+		t.Errorf("Expected RevealedCount to be 2, got %d", game.RevealedCount())
+	}
+	for _, letter := range "бди" {
+		game.UpdateGuessedWord(letter)
+	}
+	if game.IsWordGuessed() {
+		t.Errorf("Expected word not to be guessed without 'у'")
+	}
+	game.UpdateGuessedWord('у')
+	if !game.IsWordGuessed() || game.DisplayWord() != "абу-даби" {
+		t.Errorf("Expected word to be guessed, got %s", game.DisplayWord())
+	}
+}
@@ -0,0 +1,34 @@
+package domain
+
+// GuessResult — исход хода игрока.
+type GuessResult string
+
+const (
+	GuessCorrect GuessResult = "correct" // Буква есть в слове
+	GuessWrong   GuessResult = "wrong"   // Буквы или слова нет, попытки списаны
+	GuessRepeat  GuessResult = "repeat"  // Буква уже называлась, ход не засчитан
+	GuessWon     GuessResult = "won"     // Слово отгадано
+	GuessLost    GuessResult = "lost"    // Попытки закончились
+)
+
+// GuessOutcome описывает результат хода без готовых текстов для игрока.
+type GuessOutcome struct {
+	Result       GuessResult
+	Letter       rune   // Названная буква; 0, если отгадывалось слово
+	Word         string // Названное слово; пусто, если называлась буква
+	Points       int    // Изменение счёта за ход
+	Positions    []int  // Позиции, открытые этим ходом
+	AttemptsLeft int
+	WordProgress string
+	Answer       string // Загаданное слово; заполняется, когда игра окончена
+}
+
+// IsGameOver сообщает, что ход завершил игру.
+func (o GuessOutcome) IsGameOver() bool {
+	return o.Result == GuessWon || o.Result == GuessLost
+}
+
+// Counts сообщает, что ход засчитан: повтор буквы не тратит ход.
+func (o GuessOutcome) Counts() bool {
+	return o.Result != GuessRepeat
+}
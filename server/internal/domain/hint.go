@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// HintMode — какую подсказку получает игрок в комнате.
+type HintMode string
+
+const (
+	HintLetter     HintMode = "letter"     // Открыть одну букву слова
+	HintDefinition HintMode = "definition" // Показать текст подсказки из банка слов
+)
+
+// Стоимость подсказок в очках.
+const (
+	letterHintCost     = 15
+	definitionHintCost = 10
+)
+
+var (
+	// ErrNoHintsLeft возвращается, когда лимит подсказок в игре исчерпан.
+	ErrNoHintsLeft = errors.New("no hints left")
+	// ErrHintUnavailable возвращается, когда открыть букву нельзя: осталась последняя.
+	ErrHintUnavailable = errors.New("hint is not available")
+)
+
+// Hint — выданная игроку подсказка.
+type Hint struct {
+	Kind         HintMode
+	Letter       rune   // Открытая буква (для HintLetter)
+	Text         string // Текст подсказки (для HintDefinition)
+	Cost         int    // Списанные очки
+	HintsLeft    int    // Сколько подсказок ещё доступно в игре
+	WordProgress string // Слово после подсказки
+}
+
+// ParseHintMode разбирает режим подсказок из запроса. Пустая строка означает подсказку буквой.
+func ParseHintMode(mode string) (HintMode, error) {
+	switch HintMode(mode) {
+	case "", HintLetter:
+		return HintLetter, nil
+	case HintDefinition:
+		return HintDefinition, nil
+	}
+	return "", fmt.Errorf("unknown hint mode: %s", mode)
+}
+
+// HintLimit возвращает число подсказок на игру для уровня сложности.
+func HintLimit(difficulty string) int {
+	switch difficulty {
+	case "easy":
+		return 3
+	case "hard":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// SetHints задаёт текст подсказки и лимит подсказок для игры игрока.
+// В общем слове лимит один на всех.
+func (gsm *GameStateManager) SetHints(username PlayerUsername, text string, limit int) {
+	gsm.mu.Lock()
+	defer gsm.mu.Unlock()
+	if game, exists := gsm.games[username]; exists {
+		game.Hint = text
+		game.HintsLeft = limit
+	}
+}
+
+// RequestHint выдаёт игроку подсказку и списывает за неё очки.
+// Текст подсказки показывается один раз; если его нет или он уже показан, открывается буква.
+func (gsm *GameStateManager) RequestHint(player *Player, mode HintMode) (Hint, error) {
+	gsm.mu.Lock()
+	defer gsm.mu.Unlock()
+	username := PlayerUsername(player.Username)
+	game, err := gsm.playableGame(username)
+	if err != nil {
+		return Hint{}, err
+	}
+	if game.HintsLeft <= 0 {
+		return Hint{}, ErrNoHintsLeft
+	}
+
+	var hint Hint
+	if mode == HintDefinition && game.Hint != "" && !game.HintShown {
+		game.HintShown = true
+		hint = Hint{Kind: HintDefinition, Text: game.Hint, Cost: definitionHintCost}
+	} else {
+		letter, ok := game.RevealLetter()
+		if !ok {
+			return Hint{}, ErrHintUnavailable
+		}
+		hint = Hint{Kind: HintLetter, Letter: letter, Cost: letterHintCost}
+	}
+
+	game.HintsLeft--
+	gsm.addScore(game, username, -hint.Cost)
+	hint.HintsLeft = game.HintsLeft
+	hint.WordProgress = game.DisplayWord()
+	return hint, nil
+}
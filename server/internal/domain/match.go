@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MaxRounds — наибольшее число раундов в матче.
+const MaxRounds = 10
+
+// Match — серия раундов, которые игроки комнаты проходят подряд.
+// Состояние матча защищено блокировкой комнаты.
+type Match struct {
+	Rounds       int                      `json:"rounds"`
+	CurrentRound int                      `json:"current_round"`
+	RoundScores  []map[PlayerUsername]int `json:"round_scores"` // Очки игроков в каждом завершённом раунде
+	Totals       map[PlayerUsername]int   `json:"totals"`       // Сумма очков за матч
+}
+
+// MatchStanding — место игрока по итогам матча.
+type MatchStanding struct {
+	Username PlayerUsername
+	Place    int
+	Total    int
+}
+
+// ParseRounds проверяет число раундов из запроса. Ноль означает один раунд.
+func ParseRounds(rounds int) (int, error) {
+	if rounds == 0 {
+		return 1, nil
+	}
+	if rounds < 1 || rounds > MaxRounds {
+		return 0, fmt.Errorf("rounds must be between 1 and %d", MaxRounds)
+	}
+	return rounds, nil
+}
+
+func NewMatch(rounds int) *Match {
+	return &Match{
+		Rounds: rounds,
+		Totals: make(map[PlayerUsername]int),
+	}
+}
+
+// IsFinished проверяет, сыграны ли все раунды.
+func (m *Match) IsFinished() bool {
+	return len(m.RoundScores) >= m.Rounds
+}
+
+// Standings возвращает места игроков по сумме очков.
+func (m *Match) Standings() []MatchStanding {
+	standings := make([]MatchStanding, 0, len(m.Totals))
+	for username, total := range m.Totals {
+		standings = append(standings, MatchStanding{Username: username, Total: total})
+	}
+	sort.Slice(standings, func(i, j int) bool {
+		if standings[i].Total != standings[j].Total {
+			return standings[i].Total > standings[j].Total
+		}
+		return standings[i].Username < standings[j].Username
+	})
+	for i := range standings {
+		standings[i].Place = i + 1
+	}
+	return standings
+}
+
+func (m *Match) clone() Match {
+	copied := *m
+	copied.RoundScores = make([]map[PlayerUsername]int, len(m.RoundScores))
+	for i, scores := range m.RoundScores {
+		copied.RoundScores[i] = copyScores(scores)
+	}
+	copied.Totals = copyScores(m.Totals)
+	return copied
+}
+
+func copyScores(scores map[PlayerUsername]int) map[PlayerUsername]int {
+	copied := make(map[PlayerUsername]int, len(scores))
+	for username, score := range scores {
+		copied[username] = score
+	}
+	return copied
+}
+
+// StartMatch начинает новый матч с числом раундов из настроек комнаты.
+func (r *Room) StartMatch() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Match = NewMatch(r.Rounds)
+}
+
+// BeginRound делает игры раунда текущими и переводит комнату в игру.
+func (r *Room) BeginRound(round int, stateManager *GameStateManager) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.StateManager = stateManager
+	r.RoomState = InProgress
+	if r.Match != nil {
+		r.Match.CurrentRound = round
+	}
+}
+
+// CurrentRound возвращает номер текущего раунда вместе с его играми.
+// Ноль означает, что матч не начат.
+func (r *Room) CurrentRound() (int, *GameStateManager) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.Match == nil {
+		return 0, r.StateManager
+	}
+	return r.Match.CurrentRound, r.StateManager
+}
+
+// CompleteRound записывает очки завершённого раунда и возвращает копию матча.
+// false — раунд уже завершён или сменился.
+func (r *Room) CompleteRound(round int, scores map[PlayerUsername]int) (Match, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Match == nil || r.Match.CurrentRound != round || len(r.Match.RoundScores) >= round {
+		return Match{}, false
+	}
+
+	r.Match.RoundScores = append(r.Match.RoundScores, copyScores(scores))
+	for username, score := range scores {
+		r.Match.Totals[username] += score
+	}
+	if r.Match.IsFinished() {
+		r.RoomState = GameOver
+	}
+	return r.Match.clone(), true
+}
+
+// MatchState возвращает копию текущего матча.
+func (r *Room) MatchState() (Match, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.Match == nil {
+		return Match{}, false
+	}
+	return r.Match.clone(), true
+}
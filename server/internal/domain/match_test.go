@@ -0,0 +1,34 @@
+package domain
+
+import "testing"
+
+func TestMatchRounds(t *testing.T) {
+	room := &Room{Players: make(map[string]*Player), RoomSettings: RoomSettings{Rounds: 2}}
+	room.StartMatch()
+	room.BeginRound(1, NewGameStateManager())
+
+	if _, ok := room.CompleteRound(2, nil); ok {
+		t.Fatalf("round 2 has not started yet")
+	}
+	match, ok := room.CompleteRound(1, map[PlayerUsername]int{"alice": 30, "bob": 50})
+	if !ok || match.IsFinished() {
+		t.Fatalf("expected round 1 to complete without finishing the match")
+	}
+	if _, ok := room.CompleteRound(1, map[PlayerUsername]int{"alice": 30}); ok {
+		t.Fatalf("round must complete only once")
+	}
+
+	room.BeginRound(2, NewGameStateManager())
+	match, ok = room.CompleteRound(2, map[PlayerUsername]int{"alice": 40, "bob": 10})
+	if !ok || !match.IsFinished() || room.RoomState != GameOver {
+		t.Fatalf("expected the match to finish after round 2")
+	}
+
+	standings := match.Standings()
+	if standings[0].Username != "alice" || standings[0].Total != 70 || standings[1].Total != 60 {
+		t.Errorf("unexpected standings: %+v", standings)
+	}
+	if len(match.RoundScores) != 2 || match.RoundScores[1]["bob"] != 10 {
+		t.Errorf("unexpected round scores: %+v", match.RoundScores)
+	}
+}
@@ -26,3 +26,14 @@ func NewPlayer(
 		LastActive:  time.Now(), // Инициализируем время активности
 	}
 }
+
+// forRoom возвращает запись игрока для комнаты. Очки и подключение у каждой комнаты свои,
+// поэтому общая запись игрока из репозитория в комнаты не попадает.
+func (p *Player) forRoom() *Player {
+	return &Player{
+		Username:    p.Username,
+		Conn:        p.Conn,
+		IsConnected: true,
+		LastActive:  time.Now(),
+	}
+}
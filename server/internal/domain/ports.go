@@ -13,38 +13,66 @@ type IRoomRepository interface {
 	GetAllRooms() []*Room
 }
 
+type ISnapshotRepository interface {
+	Save(rooms []RoomSnapshot) error
+	Load() ([]RoomSnapshot, error)
+}
+
 type IRoomController interface {
 	CheckUsernameUniqueness(username string) bool
-	CreateRoom(ctx context.Context, player string, roomID, password, category, difficulty string) (*Room, error)
-	UpdateRoom(roomID string, clientKey ClientKey, newPassword, newCategory, newDifficulty *string) (*Room, error)
-	JoinRoom(ctx context.Context, username, roomID, password string) (*Room, error)
-	StartGame(clientKey ClientKey, roomID string) error
-	MakeGuess(clientKey ClientKey, roomID string, letter rune) (bool, string, error)
-	GetRoomState(roomID, password string) (*Room, error)
-	DeleteRoom(clientKey ClientKey, roomID string) error
-	LeaveRoom(clientKey ClientKey, roomID string) error
+	CreateRoom(ctx context.Context, player string, roomID, password string, options RoomOptions) (*Room, error)
+	UpdateRoom(roomID string, username string, newPassword *string, options RoomOptions) (*Room, error)
+	JoinRoom(ctx context.Context, username, roomID, password string, spectator bool) (*Room, error)
+	StartGame(username string, roomID string) error
+	MakeGuess(username string, roomID string, letter rune) (GuessOutcome, GameState, error)
+	GuessWord(username string, roomID string, word string) (GuessOutcome, GameState, error)
+	RequestHint(username string, roomID string) (Hint, error)
+	GetRoomState(username, roomID, password string) (*Room, error)
+	DeleteRoom(username string, roomID string) error
+	LeaveRoom(username string, roomID string) error
 	HandleOwnerChange(room *Room) error
 	CleanupRooms(timeoutSeconds int)
-	GetGameState(roomID string) (map[string]*GameState, error)
+	GetGameState(username, roomID string) (map[string]*GameState, error)
 	GetAllRooms() ([]*Room, error)
-	GetLeaderboard() (map[string]int, error)
+	GetLeaderboard() ([]PlayerStats, error)
+}
+
+type IAuthService interface {
+	Register(ctx context.Context, username, password string) (string, error)
+	Login(ctx context.Context, username, password string) (string, error)
 }
 
 type IPlayerRepository interface {
-	AddPlayer(key ClientKey, player *Player) error
+	CreateAccount(account *Account) error
+	GetAccount(username string) (*Account, error)
+	UpdateAccount(account *Account) error
+	AddPlayer(player *Player) error
 	PlayerExists(username string) bool
-	RemovePlayer(key ClientKey) error
-	GetPlayerByKey(key ClientKey) (*Player, error)
+	GetPlayerByUsername(username string) (*Player, error)
 	RemovePlayerByUsername(username string) error
 	GetAllPlayers() []*Player
 	GetPlayerCount() int
 	MonitorConnections(timeout time.Duration, inactivePlayersChan chan<- []string)
-	UpdatePlayerActivity(key ClientKey) error
-	GetPlayerUsernamesAndScores() map[string]int
+	UpdatePlayerActivity(username string) error
+	RecordGameResult(username string, score int, won bool) error
+	GetLeaderboard() ([]PlayerStats, error)
 }
 
 type IGameService interface {
 	StartGame(room *Room) error
-	MakeGuess(room *Room, player *Player, letter rune) (bool, string, error)
+	AdvanceMatch(room *Room) error
+	MakeGuess(room *Room, player *Player, letter rune) (GuessOutcome, error)
+	GuessWord(room *Room, player *Player, word string) (GuessOutcome, error)
+	RequestHint(room *Room, player *Player) (Hint, error)
+	TimeoutTurn(room *Room, username PlayerUsername, started time.Time) (GuessOutcome, bool, error)
+	TimeoutGame(room *Room) ([]PlayerUsername, error)
+	CloseRoom(roomID string)
 	GetGameState(room *Room) (map[string]*GameState, error)
 }
+
+// IRandomSource — источник случайных чисел для выбора слов.
+// Реализации должны быть безопасны для одновременного вызова из разных горутин.
+type IRandomSource interface {
+	Intn(n int) int
+	Shuffle(n int, swap func(i, j int))
+}
@@ -0,0 +1,76 @@
+package domain
+
+import "sort"
+
+// racePlacementBonus — бонус за занятое в гонке место: первое, второе, третье.
+var racePlacementBonus = []int{100, 50, 25}
+
+// RacePlacement — место игрока по итогам гонки.
+type RacePlacement struct {
+	Username PlayerUsername
+	Place    int
+	Bonus    int // Бонус за место, уже добавленный к очкам
+	Score    int // Очки за игру с учётом бонуса
+}
+
+// RaceResult — итог гонки.
+type RaceResult struct {
+	Winner    PlayerUsername
+	Word      string
+	Standings []RacePlacement
+}
+
+// FinishRace фиксирует победу игрока, первым отгадавшего слово.
+// Незавершённые игры соперников закрываются, места распределяются по числу открытых букв
+// и оставшимся попыткам, за места начисляются бонусы. Повторный вызов возвращает false.
+func (gsm *GameStateManager) FinishRace(username PlayerUsername) (*RaceResult, bool) {
+	gsm.mu.Lock()
+	defer gsm.mu.Unlock()
+
+	game, exists := gsm.games[username]
+	if gsm.winner != "" || !exists || !game.IsWordGuessed() {
+		return nil, false
+	}
+	gsm.winner = username
+	result := &RaceResult{Winner: username, Word: game.Word}
+
+	others := make([]PlayerUsername, 0, len(gsm.games)-1)
+	for other, otherGame := range gsm.games {
+		if other == username {
+			continue
+		}
+		if !otherGame.IsOver() {
+			otherGame.Closed = true
+		}
+		others = append(others, other)
+	}
+	sort.Slice(others, func(i, j int) bool {
+		a, b := gsm.games[others[i]], gsm.games[others[j]]
+		if a.RevealedCount() != b.RevealedCount() {
+			return a.RevealedCount() > b.RevealedCount()
+		}
+		if a.AttemptsLeft != b.AttemptsLeft {
+			return a.AttemptsLeft > b.AttemptsLeft
+		}
+		return others[i] < others[j]
+	})
+
+	for i, player := range append([]PlayerUsername{username}, others...) {
+		placement := RacePlacement{Username: player, Place: i + 1}
+		if i < len(racePlacementBonus) {
+			placement.Bonus = racePlacementBonus[i]
+		}
+		game := gsm.games[player]
+		game.Score += placement.Bonus
+		placement.Score = game.Score
+		result.Standings = append(result.Standings, placement)
+	}
+	return result, true
+}
+
+// RaceWinner возвращает победителя гонки, если он уже определён.
+func (gsm *GameStateManager) RaceWinner() (PlayerUsername, bool) {
+	gsm.mu.RLock()
+	defer gsm.mu.RUnlock()
+	return gsm.winner, gsm.winner != ""
+}
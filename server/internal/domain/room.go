@@ -4,9 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"hangman/internal/events"
 	tcp_server "hangman/pkg/tcp-server"
 	"hangman/pkg/utils"
-	"net"
+	"sort"
 	"sync"
 	"time"
 )
@@ -23,19 +24,19 @@ type Room struct {
 	ID                 string
 	Owner              *string
 	Players            map[string]*Player // Используем map для хранения игроков
+	Spectators         map[string]*Player // Зрители: получают события, но не играют и не занимают мест
 	notificationServer *tcp_server.NotificationServer
 	LastActivity       time.Time
-	MaxPlayers         int
 	Password           string
-	Category           string
-	Difficulty         string
-	StateManager       *GameStateManager
-	RoomState          roomState
-	mu                 sync.RWMutex
+	RoomSettings
+	Match        *Match // Текущий матч; nil, пока игра не начиналась
+	StateManager *GameStateManager
+	RoomState    roomState
+	mu           sync.RWMutex
 }
 
 // Конструктор для Room
-func NewRoom(ctx context.Context, id string, owner *string, maxPlayers int, password, category, difficulty string) *Room {
+func NewRoom(ctx context.Context, id string, owner *string, password string, settings RoomSettings) *Room {
 	// Извлекаем логгер из контекста
 	logger, ok := tcp_server.GetLogger(ctx)
 	if !ok {
@@ -50,10 +51,9 @@ func NewRoom(ctx context.Context, id string, owner *string, maxPlayers int, pass
 		Password:           password,
 		Owner:              owner,
 		Players:            make(map[string]*Player),
+		Spectators:         make(map[string]*Player),
 		LastActivity:       time.Now(),
-		MaxPlayers:         maxPlayers,
-		Category:           category,
-		Difficulty:         difficulty,
+		RoomSettings:       settings,
 		RoomState:          Waiting,
 		notificationServer: notificationSrv,
 	}
@@ -71,11 +71,45 @@ func (r *Room) MonitorContext(ctx context.Context, username string) {
 			fmt.Printf("Player %s: context canceled, kicking from room (logger not found)\n", username)
 		}
 		//time.Sleep(3 * time.Second)
+		// Во время игры только помечаем игрока отключённым, чтобы он мог вернуться через JOIN_ROOM
+		if r.disconnectInProgress(username) {
+			r.SyncTurn()
+			return
+		}
 		// Кикаем игрока
 		r.KickPlayer(username)
+		r.SyncTurn()
 	}()
 }
 
+func (r *Room) disconnectInProgress(username string) bool {
+	r.Lock()
+	defer r.Unlock()
+
+	player, exists := r.Players[username]
+	if !exists || r.RoomState != InProgress {
+		return false
+	}
+	player.IsConnected = false
+	return true
+}
+
+// ReconnectPlayer отмечает вернувшегося игрока подключённым к его текущему соединению.
+// Очки, накопленные в комнате до отключения, сохраняются.
+func (r *Room) ReconnectPlayer(player *Player) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	member, exists := r.Players[player.Username]
+	if !exists {
+		r.Players[player.Username] = player.forRoom()
+		return
+	}
+	member.Conn = player.Conn
+	member.IsConnected = true
+	member.LastActive = time.Now()
+}
+
 func (r *Room) KickPlayer(username string) {
 	r.Lock()
 	defer r.Unlock()
@@ -84,6 +118,7 @@ func (r *Room) KickPlayer(username string) {
 		// Удаляем игрока из комнаты
 		delete(r.Players, username)
 	}
+	delete(r.Spectators, username)
 }
 
 func (r *Room) SetState(state roomState) {
@@ -130,7 +165,7 @@ func (r *Room) AddPlayer(player *Player) {
 		return // Не добавляем игрока, если комната заполнена
 	}
 
-	r.Players[player.Username] = player
+	r.Players[player.Username] = player.forRoom()
 }
 
 func (r *Room) HasPlayer(username string) bool {
@@ -141,6 +176,38 @@ func (r *Room) HasPlayer(username string) bool {
 	return exists
 }
 
+// AddSpectator добавляет зрителя. Зрители не учитываются в MaxPlayers.
+func (r *Room) AddSpectator(player *Player) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.Spectators == nil {
+		r.Spectators = make(map[string]*Player)
+	}
+	r.Spectators[player.Username] = player.forRoom()
+}
+
+func (r *Room) HasSpectator(username string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, exists := r.Spectators[username]
+	return exists
+}
+
+// GetAllSpectators возвращает имена зрителей, отсортированные по алфавиту.
+func (r *Room) GetAllSpectators() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	spectators := make([]string, 0, len(r.Spectators))
+	for username := range r.Spectators {
+		spectators = append(spectators, username)
+	}
+	sort.Strings(spectators)
+	return spectators
+}
+
 func (r *Room) GetPlayerCount() int {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -168,36 +235,133 @@ func (r *Room) NotifyPlayers(event string, payload interface{}) error {
 		return fmt.Errorf("failed to serialize event payload: %w", err)
 	}
 
-	// Получение списка подключенных клиентов
-	clients := r.getConnectedClientsIPs()
+	// Получение списка подключенных игроков
+	players := r.getConnectedPlayers()
 
-	if len(clients) == 0 {
+	if len(players) == 0 {
 		logger.Debug("no connected clients to notify")
 	}
 
 	// Отправка уведомлений
-	r.notificationServer.Notify(event, message, clients)
+	r.notificationServer.Notify(event, message, players)
 
 	return nil
 }
 
-// Вспомогательный метод для получения списка подключенных клиентов
-func (r *Room) getConnectedClientsIPs() []string {
+// NotifyOthers рассылает событие всем подключённым игрокам, кроме except. Зрители его не получают.
+func (r *Room) NotifyOthers(event string, payload interface{}, except string) error {
+	r.mu.RLock()
+	var players []string
+	for username, player := range r.Players {
+		if player.IsConnected && username != except {
+			players = append(players, username)
+		}
+	}
+	r.mu.RUnlock()
+	return r.notify(event, payload, players)
+}
+
+// NotifySpectators рассылает событие только зрителям комнаты.
+func (r *Room) NotifySpectators(event string, payload interface{}) error {
+	return r.notify(event, payload, r.GetAllSpectators())
+}
+
+func (r *Room) notify(event string, payload interface{}, recipients []string) error {
+	if len(recipients) == 0 {
+		return nil
+	}
+	message, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to serialize event payload: %w", err)
+	}
+	r.notificationServer.Notify(event, message, recipients)
+	return nil
+}
+
+// Вспомогательный метод для получения списка подключенных игроков и зрителей
+func (r *Room) getConnectedPlayers() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var players []string
+	for username, player := range r.Players {
+		if player.IsConnected {
+			players = append(players, username)
+		}
+	}
+	for username := range r.Spectators {
+		players = append(players, username)
+	}
+	return players
+}
+
+// AwardPoints добавляет игроку очки комнаты.
+func (r *Room) AwardPoints(username PlayerUsername, points int) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	var clientIPs []string
-	for _, player := range r.Players {
-		if player.IsConnected && player.Conn != nil {
-			remoteAddr := (*player.Conn).RemoteAddr().String()
-			// Извлекаем только IP-адрес из "IP:port"
-			host, _, err := net.SplitHostPort(remoteAddr)
-			if err == nil {
-				clientIPs = append(clientIPs, host)
-			} else {
-				fmt.Printf("failed to parse remote address: %v\n", err)
-			}
+	if player, exists := r.Players[string(username)]; exists {
+		player.Score += points
+	}
+}
+
+// TurnOrder возвращает порядок ходов: сначала владелец, затем остальные по имени.
+func (r *Room) TurnOrder() []PlayerUsername {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	order := make([]PlayerUsername, 0, len(r.Players))
+	for username := range r.Players {
+		if r.Owner == nil || username != *r.Owner {
+			order = append(order, PlayerUsername(username))
 		}
 	}
-	return clientIPs
+	sort.Slice(order, func(i, j int) bool { return order[i] < order[j] })
+	if r.Owner != nil {
+		if _, exists := r.Players[*r.Owner]; exists {
+			order = append([]PlayerUsername{PlayerUsername(*r.Owner)}, order...)
+		}
+	}
+	return order
+}
+
+// IsPlayerActive проверяет, что игрок в комнате и подключён.
+func (r *Room) IsPlayerActive(username PlayerUsername) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	player, exists := r.Players[string(username)]
+	return exists && player.IsConnected
+}
+
+// NextTurn передаёт ход следующему активному игроку и уведомляет об этом комнату.
+func (r *Room) NextTurn() error {
+	r.mu.RLock()
+	stateManager := r.StateManager
+	r.mu.RUnlock()
+	if stateManager == nil {
+		return nil
+	}
+
+	next, ok := stateManager.AdvanceTurn(r.IsPlayerActive)
+	if !ok {
+		return nil
+	}
+	return r.NotifyPlayers("TurnChanged", events.TurnChangedEventPayload{Username: string(next)})
+}
+
+// SyncTurn передаёт ход дальше, если игрок, чей сейчас ход, вышел или отключился.
+func (r *Room) SyncTurn() error {
+	r.mu.RLock()
+	stateManager := r.StateManager
+	r.mu.RUnlock()
+	if stateManager == nil {
+		return nil
+	}
+
+	holder, ok := stateManager.CurrentTurn()
+	if !ok || r.IsPlayerActive(holder) {
+		return nil
+	}
+	return r.NextTurn()
 }
@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Ограничения настроек комнаты.
+const (
+	DefaultMaxPlayers = 3
+	MaxRoomPlayers    = 10
+	MaxAttempts       = 20
+	MinTurnSeconds    = 10
+	MaxTurnSeconds    = 300
+	MinGameSeconds    = 30
+	MaxGameSeconds    = 3600
+	MaxTagLength      = 32
+)
+
+// RoomSettings — настройки комнаты, которые задаёт владелец.
+type RoomSettings struct {
+	Category    string   `json:"category"`
+	Difficulty  string   `json:"difficulty"`
+	Mode        GameMode `json:"mode,omitempty"`
+	HintMode    HintMode `json:"hint_mode,omitempty"` // Какие подсказки выдаются игрокам
+	Rounds      int      `json:"rounds,omitempty"`    // Число раундов в матче
+	MaxPlayers  int      `json:"max_players"`
+	Private     bool     `json:"private,omitempty"`      // Не показывать комнату в общем списке
+	Attempts    int      `json:"attempts,omitempty"`     // Попыток на слово; 0 — по сложности слова
+	TurnSeconds int      `json:"turn_seconds,omitempty"` // Время на ход; 0 — без ограничения
+	GameSeconds int      `json:"game_seconds,omitempty"` // Время на слово в раунде; 0 — без ограничения
+	Tag         string   `json:"tag,omitempty"`          // Тег слов; пусто — любые слова категории
+}
+
+// RoomOptions — настройки из запроса. nil означает значение по умолчанию при создании
+// и прежнее значение при обновлении комнаты.
+type RoomOptions struct {
+	Category    *string
+	Difficulty  *string
+	Mode        *string
+	HintMode    *string
+	Rounds      *int
+	MaxPlayers  *int
+	Private     *bool
+	Attempts    *int
+	TurnSeconds *int
+	GameSeconds *int
+	Tag         *string
+}
+
+// DefaultRoomSettings возвращает настройки новой комнаты.
+func DefaultRoomSettings() RoomSettings {
+	return RoomSettings{
+		Difficulty: "medium",
+		Mode:       ModeClassic,
+		HintMode:   HintLetter,
+		Rounds:     1,
+		MaxPlayers: DefaultMaxPlayers,
+	}
+}
+
+// Apply возвращает настройки с применёнными опциями или ошибку, если опция недопустима.
+func (s RoomSettings) Apply(options RoomOptions) (RoomSettings, error) {
+	var err error
+	if options.Category != nil {
+		s.Category = *options.Category
+	}
+	if options.Difficulty != nil {
+		if s.Difficulty, err = ParseDifficulty(*options.Difficulty); err != nil {
+			return s, err
+		}
+	}
+	if options.Mode != nil {
+		if s.Mode, err = ParseGameMode(*options.Mode); err != nil {
+			return s, err
+		}
+	}
+	if options.HintMode != nil {
+		if s.HintMode, err = ParseHintMode(*options.HintMode); err != nil {
+			return s, err
+		}
+	}
+	if options.Rounds != nil {
+		if s.Rounds, err = ParseRounds(*options.Rounds); err != nil {
+			return s, err
+		}
+	}
+	if options.MaxPlayers != nil {
+		if *options.MaxPlayers < 1 || *options.MaxPlayers > MaxRoomPlayers {
+			return s, fmt.Errorf("max_players must be between 1 and %d", MaxRoomPlayers)
+		}
+		s.MaxPlayers = *options.MaxPlayers
+	}
+	if options.Private != nil {
+		s.Private = *options.Private
+	}
+	if options.Attempts != nil {
+		if *options.Attempts < 0 || *options.Attempts > MaxAttempts {
+			return s, fmt.Errorf("attempts must be between 1 and %d, or 0 for automatic", MaxAttempts)
+		}
+		s.Attempts = *options.Attempts
+	}
+	if options.TurnSeconds != nil {
+		seconds := *options.TurnSeconds
+		if seconds != 0 && (seconds < MinTurnSeconds || seconds > MaxTurnSeconds) {
+			return s, fmt.Errorf("turn_seconds must be between %d and %d, or 0 for no limit", MinTurnSeconds, MaxTurnSeconds)
+		}
+		s.TurnSeconds = seconds
+	}
+	if options.GameSeconds != nil {
+		seconds := *options.GameSeconds
+		if seconds != 0 && (seconds < MinGameSeconds || seconds > MaxGameSeconds) {
+			return s, fmt.Errorf("game_seconds must be between %d and %d, or 0 for no limit", MinGameSeconds, MaxGameSeconds)
+		}
+		s.GameSeconds = seconds
+	}
+	if options.Tag != nil {
+		tag := strings.ToLower(strings.TrimSpace(*options.Tag))
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return s, fmt.Errorf("tag must be at most %d characters", MaxTagLength)
+		}
+		s.Tag = tag
+	}
+	return s, nil
+}
+
+// ParseDifficulty проверяет уровень сложности из запроса. Пустая строка означает medium.
+func ParseDifficulty(difficulty string) (string, error) {
+	switch difficulty {
+	case "":
+		return "medium", nil
+	case "easy", "medium", "hard":
+		return difficulty, nil
+	}
+	return "", fmt.Errorf("unknown difficulty: %s", difficulty)
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRoomSettingsApply(t *testing.T) {
+	players, private, seconds, tag := 6, true, 45, " Европа "
+	settings, err := DefaultRoomSettings().Apply(RoomOptions{MaxPlayers: &players, Private: &private, TurnSeconds: &seconds, Tag: &tag})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.MaxPlayers != 6 || !settings.Private || settings.TurnSeconds != 45 || settings.Tag != "европа" {
+		t.Errorf("options not applied: %+v", settings)
+	}
+	if settings.Mode != ModeClassic || settings.Rounds != 1 || settings.Difficulty != "medium" {
+		t.Errorf("defaults lost: %+v", settings)
+	}
+
+	// Без опций настройки не меняются
+	if same, err := settings.Apply(RoomOptions{}); err != nil || same != settings {
+		t.Errorf("empty options changed settings: %+v, %v", same, err)
+	}
+
+	zero, crowd, many, short, bad := 0, MaxRoomPlayers+1, MaxAttempts+1, MinTurnSeconds-1, "nightmare"
+	longTag := strings.Repeat("я", MaxTagLength+1)
+	invalid := []RoomOptions{
+		{MaxPlayers: &zero},
+		{MaxPlayers: &crowd},
+		{Attempts: &many},
+		{TurnSeconds: &short},
+		{GameSeconds: &short},
+		{Difficulty: &bad},
+		{Mode: &bad},
+		{Tag: &longTag},
+	}
+	for _, options := range invalid {
+		if _, err := settings.Apply(options); err == nil {
+			t.Errorf("expected error for %+v", options)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "testing"
+
+func TestSpectatorsDoNotTakeSeats(t *testing.T) {
+	room := &Room{Players: make(map[string]*Player), RoomSettings: RoomSettings{MaxPlayers: 1}}
+	room.AddPlayer(NewPlayer(nil, "alice", 0))
+	room.AddSpectator(NewPlayer(nil, "carol", 0))
+	room.AddSpectator(NewPlayer(nil, "bob", 0))
+
+	if room.GetPlayerCount() != 1 || room.HasPlayer("bob") {
+		t.Fatalf("spectators must not be counted as players")
+	}
+	if spectators := room.GetAllSpectators(); len(spectators) != 2 || spectators[0] != "bob" {
+		t.Fatalf("expected sorted spectators, got %v", spectators)
+	}
+
+	room.KickPlayer("bob")
+	if room.HasSpectator("bob") || !room.HasSpectator("carol") {
+		t.Errorf("expected only bob to leave, got %v", room.GetAllSpectators())
+	}
+}
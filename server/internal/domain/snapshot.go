@@ -0,0 +1,180 @@
+package domain
+
+import (
+	tcp_server "hangman/pkg/tcp-server"
+	"time"
+	"unicode/utf8"
+)
+
+// PlayerSnapshot — сохранённое состояние игрока в комнате.
+type PlayerSnapshot struct {
+	Username string `json:"username"`
+	Score    int    `json:"score"`
+}
+
+// RoomSnapshot — сохранённое состояние комнаты вместе с играми игроков.
+type RoomSnapshot struct {
+	ID           string           `json:"id"`
+	Owner        string           `json:"owner"`
+	Players      []PlayerSnapshot `json:"players"`
+	LastActivity time.Time        `json:"last_activity"`
+	Password     string           `json:"password"`
+	RoomSettings
+	Match     *Match                  `json:"match,omitempty"`
+	RoomState string                  `json:"room_state"`
+	Games     map[PlayerUsername]Game `json:"games,omitempty"`
+	Turns     *TurnSnapshot           `json:"turns,omitempty"`
+	// Категория, из которой розданы слова игр; в старых снимках пуста
+	GamesCategory string `json:"games_category,omitempty"`
+}
+
+// TurnSnapshot — сохранённая очередь ходов и личные очки в общем слове.
+type TurnSnapshot struct {
+	Order   []PlayerUsername       `json:"order"`
+	Current int                    `json:"current"`
+	Scores  map[PlayerUsername]int `json:"scores"`
+}
+
+// Snapshot возвращает копию состояния комнаты для сохранения на диск.
+func (r *Room) Snapshot() RoomSnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshot := RoomSnapshot{
+		ID:           r.ID,
+		LastActivity: r.LastActivity,
+		Password:     r.Password,
+		RoomSettings: r.RoomSettings,
+		RoomState:    string(r.RoomState),
+	}
+	if r.Match != nil {
+		match := r.Match.clone()
+		snapshot.Match = &match
+	}
+	if r.Owner != nil {
+		snapshot.Owner = *r.Owner
+	}
+	for username, player := range r.Players {
+		snapshot.Players = append(snapshot.Players, PlayerSnapshot{Username: username, Score: player.Score})
+	}
+	if r.StateManager != nil {
+		snapshot.Games = r.StateManager.Snapshot()
+		snapshot.Turns = r.StateManager.TurnSnapshot()
+		snapshot.GamesCategory = r.StateManager.Category()
+	}
+	return snapshot
+}
+
+// RestoreRoom восстанавливает комнату из снимка.
+// Игроки восстанавливаются отключёнными и возвращаются в игру через JOIN_ROOM.
+// Зрители в снимок не попадают и подключаются заново.
+func RestoreRoom(snapshot RoomSnapshot, notificationSrv *tcp_server.NotificationServer) *Room {
+	owner := snapshot.Owner
+	room := &Room{
+		ID:                 snapshot.ID,
+		Owner:              &owner,
+		Players:            make(map[string]*Player),
+		Spectators:         make(map[string]*Player),
+		LastActivity:       snapshot.LastActivity,
+		Password:           snapshot.Password,
+		RoomSettings:       snapshot.RoomSettings,
+		Match:              snapshot.Match,
+		RoomState:          roomState(snapshot.RoomState),
+		notificationServer: notificationSrv,
+	}
+	for _, p := range snapshot.Players {
+		player := NewPlayer(nil, p.Username, p.Score)
+		player.IsConnected = false
+		room.Players[p.Username] = player
+	}
+	if snapshot.Games != nil {
+		room.StateManager = RestoreGameStateManager(snapshot.Games, snapshot.Turns)
+		category := snapshot.GamesCategory
+		if category == "" {
+			category = snapshot.Category
+		}
+		room.StateManager.SetCategory(category)
+	}
+	if room.Mode == "" {
+		room.Mode = ModeClassic // Снимки, сохранённые до появления режимов
+	}
+	if room.Rounds == 0 {
+		room.Rounds = 1
+	}
+	if room.HintMode == "" {
+		room.HintMode = HintLetter
+	}
+	return room
+}
+
+// Snapshot возвращает копии всех игр менеджера.
+func (gsm *GameStateManager) Snapshot() map[PlayerUsername]Game {
+	gsm.mu.RLock()
+	defer gsm.mu.RUnlock()
+
+	games := make(map[PlayerUsername]Game, len(gsm.games))
+	for username, game := range gsm.games {
+		copied := *game
+		copied.GuessedWord = append([]rune(nil), game.GuessedWord...)
+		copied.Correct = append([]rune(nil), game.Correct...)
+		copied.Wrong = append([]rune(nil), game.Wrong...)
+		games[username] = copied
+	}
+	return games
+}
+
+// TurnSnapshot возвращает копию очереди ходов или nil, если игроки играют независимо.
+func (gsm *GameStateManager) TurnSnapshot() *TurnSnapshot {
+	gsm.mu.RLock()
+	defer gsm.mu.RUnlock()
+
+	if gsm.turns == nil {
+		return nil
+	}
+	scores := make(map[PlayerUsername]int, len(gsm.scores))
+	for username, score := range gsm.scores {
+		scores[username] = score
+	}
+	return &TurnSnapshot{
+		Order:   append([]PlayerUsername(nil), gsm.turns.players...),
+		Current: gsm.turns.current,
+		Scores:  scores,
+	}
+}
+
+// RestoreGameStateManager создаёт менеджер игр из сохранённых копий.
+// При наличии очереди ходов все игроки снова получают одно общее слово.
+func RestoreGameStateManager(games map[PlayerUsername]Game, turns *TurnSnapshot) *GameStateManager {
+	gsm := NewGameStateManager()
+	if turns != nil && len(turns.Order) > 0 {
+		shared := games[turns.Order[0]]
+		shared.restoreProgress()
+		for _, username := range turns.Order {
+			gsm.games[username] = &shared
+			gsm.scores[username] = turns.Scores[username]
+		}
+		gsm.turns = &turnOrder{players: turns.Order, current: turns.Current % len(turns.Order)}
+		return gsm
+	}
+	for username, game := range games {
+		restored := game
+		restored.restoreProgress()
+		gsm.games[username] = &restored
+	}
+	return gsm
+}
+
+// restoreProgress пересобирает открытые буквы из старых снимков,
+// в которых прогресс слова хранился по байтам, а не по символам.
+func (g *Game) restoreProgress() {
+	if len(g.GuessedWord) == utf8.RuneCountInString(g.Word) {
+		return
+	}
+	opened := g.GuessedWord
+	g.GuessedWord = NewGame(g.Word, 0).GuessedWord
+	for _, r := range opened {
+		if r != 0 {
+			g.UpdateGuessedWord(r)
+		}
+	}
+}
@@ -1,27 +1,45 @@
 package domain
 
 import (
+	"errors"
 	"fmt"
 	"sync"
+	"time"
 )
 
 type PlayerUsername string
 
+// Правила отгадывания слова целиком.
+const (
+	wordGuessBonus       = 150 // Очки за верное слово
+	wordGuessPenalty     = 20  // Штраф за неверное слово
+	wordGuessAttemptCost = 3   // Сколько попыток сгорает при неверном слове
+)
+
 type GameState struct {
 	WordProgress string // Текущее состояние слова (с угаданными буквами)
 	AttemptsLeft int    // Остаток попыток
 	IsGameOver   bool   // Статус завершения игры
+	IsWon        bool   // Слово угадано
 	Score        int    // Текущий счет игрока
+	IsTurn       bool   // Сейчас ход игрока (в режиме очереди)
+	Correct      []rune // Названные буквы, которые есть в слове
+	Wrong        []rune // Названные буквы, которых в слове нет
 }
 
 type GameStateManager struct {
-	games map[PlayerUsername]*Game
-	mu    sync.RWMutex // Для защиты карты игр
+	games    map[PlayerUsername]*Game
+	turns    *turnOrder             // Очередь ходов; nil, если игроки играют независимо
+	scores   map[PlayerUsername]int // Личные очки игроков в общем слове
+	winner   PlayerUsername         // Победитель гонки
+	category string                 // Категория, из которой розданы слова
+	mu       sync.RWMutex           // Для защиты карты игр
 }
 
 func NewGameStateManager() *GameStateManager {
 	return &GameStateManager{
-		games: make(map[PlayerUsername]*Game),
+		games:  make(map[PlayerUsername]*Game),
+		scores: make(map[PlayerUsername]int),
 	}
 }
 
@@ -31,6 +49,79 @@ func (gsm *GameStateManager) AddGame(word string, username PlayerUsername, attem
 	gsm.games[username] = NewGame(word, attempts)
 }
 
+// AddSharedGame создаёт одно слово на всех игроков с общим запасом попыток.
+// Игроки ходят в порядке order. Без игроков слово не создаётся.
+func (gsm *GameStateManager) AddSharedGame(word string, order []PlayerUsername, attempts int) {
+	gsm.mu.Lock()
+	defer gsm.mu.Unlock()
+	if len(order) == 0 {
+		return
+	}
+	game := NewGame(word, attempts)
+	for _, username := range order {
+		gsm.games[username] = game
+		gsm.scores[username] = 0
+	}
+	gsm.turns = &turnOrder{players: order}
+}
+
+// SetCategory запоминает категорию, из которой розданы слова раунда.
+func (gsm *GameStateManager) SetCategory(category string) {
+	gsm.mu.Lock()
+	defer gsm.mu.Unlock()
+	gsm.category = category
+}
+
+// Category возвращает категорию слов раунда: по её алфавиту проверяются догадки,
+// даже если категорию комнаты уже сменили.
+func (gsm *GameStateManager) Category() string {
+	gsm.mu.RLock()
+	defer gsm.mu.RUnlock()
+	return gsm.category
+}
+
+// Players возвращает игроков, у которых есть игра.
+func (gsm *GameStateManager) Players() []PlayerUsername {
+	gsm.mu.RLock()
+	defer gsm.mu.RUnlock()
+	players := make([]PlayerUsername, 0, len(gsm.games))
+	for username := range gsm.games {
+		players = append(players, username)
+	}
+	return players
+}
+
+// CurrentTurn возвращает игрока, чей сейчас ход. false — если очереди нет или игра окончена.
+func (gsm *GameStateManager) CurrentTurn() (PlayerUsername, bool) {
+	gsm.mu.RLock()
+	defer gsm.mu.RUnlock()
+	if gsm.turns == nil || gsm.sharedGameOver() {
+		return "", false
+	}
+	return gsm.turns.holder(), true
+}
+
+// AdvanceTurn передаёт ход следующему игроку, для которого isActive вернул true.
+// Возвращает false, если ход не перешёл.
+func (gsm *GameStateManager) AdvanceTurn(isActive func(PlayerUsername) bool) (PlayerUsername, bool) {
+	gsm.mu.Lock()
+	defer gsm.mu.Unlock()
+	if gsm.turns == nil || gsm.sharedGameOver() {
+		return "", false
+	}
+	next, ok := gsm.turns.advance(isActive)
+	if ok {
+		gsm.games[next].TurnStarted = time.Now()
+	}
+	return next, ok
+}
+
+// sharedGameOver сообщает, завершено ли общее слово. Вызывается под блокировкой.
+func (gsm *GameStateManager) sharedGameOver() bool {
+	game := gsm.games[gsm.turns.holder()]
+	return game == nil || game.IsOver()
+}
+
 func (gsm *GameStateManager) GetState(username PlayerUsername) (GameState, error) {
 	gsm.mu.RLock()
 	defer gsm.mu.RUnlock()
@@ -38,49 +129,147 @@ func (gsm *GameStateManager) GetState(username PlayerUsername) (GameState, error
 	if !exists {
 		return GameState{}, fmt.Errorf("no game found for username: %s", username)
 	}
-	return GameState{
+	state := GameState{
 		WordProgress: game.DisplayWord(),
 		AttemptsLeft: game.AttemptsLeft,
-		IsGameOver:   game.IsWordGuessed() || game.AttemptsLeft <= 0,
+		IsGameOver:   game.IsOver(),
+		IsWon:        game.IsWordGuessed(),
 		Score:        game.Score,
-	}, nil
+		Correct:      append([]rune(nil), game.Correct...),
+		Wrong:        append([]rune(nil), game.Wrong...),
+	}
+	if gsm.turns != nil {
+		// В общем слове у каждого игрока свои очки
+		state.Score = gsm.scores[username]
+		state.IsTurn = !state.IsGameOver && gsm.turns.holder() == username
+	}
+	return state, nil
 }
 
-func (gsm *GameStateManager) MakeGuess(player *Player, letter rune) (bool, string, error) {
-	gsm.mu.RLock()
-	defer gsm.mu.RUnlock()
-	game, exists := gsm.games[PlayerUsername(player.Username)]
+// Masked возвращает состояние для зрителей: открытые буквы заменены на '*',
+// а названные буквы скрыты, чтобы зритель не мог подсказывать игрокам.
+func (s GameState) Masked() GameState {
+	s.WordProgress = MaskProgress(s.WordProgress)
+	s.Correct = nil
+	s.Wrong = nil
+	return s
+}
+
+// MaskProgress заменяет открытые буквы прогресса слова на '*'.
+func MaskProgress(progress string) string {
+	masked := []rune(progress)
+	for i, r := range masked {
+		if r != '_' && !isPreRevealed(r) {
+			masked[i] = '*'
+		}
+	}
+	return string(masked)
+}
+
+// playableGame возвращает игру, в которой игрок может сделать ход. Вызывается под блокировкой.
+func (gsm *GameStateManager) playableGame(username PlayerUsername) (*Game, error) {
+	game, exists := gsm.games[username]
 	if !exists {
-		return false, "", fmt.Errorf("no game found for username: %s", player.Username)
+		return nil, fmt.Errorf("no game found for username: %s", username)
+	}
+	if game.IsOver() {
+		return nil, fmt.Errorf("game is already over for username: %s", username)
 	}
+	if gsm.turns != nil && gsm.turns.holder() != username {
+		return nil, ErrNotYourTurn
+	}
+	return game, nil
+}
 
-	isCorrect := game.UpdateGuessedWord(letter)
+// MakeGuess проверяет букву и возвращает исход хода.
+// Повторно названная буква не тратит попытку и не меняет счёт.
+func (gsm *GameStateManager) MakeGuess(player *Player, letter rune) (GuessOutcome, error) {
+	gsm.mu.Lock()
+	defer gsm.mu.Unlock()
+	username := PlayerUsername(player.Username)
+	game, err := gsm.playableGame(username)
+	if err != nil {
+		return GuessOutcome{}, err
+	}
 
-	// Правильный ответ
-	if isCorrect {
-		game.Score += 10 // Начислить очки за правильную букву
-		player.Score += 10
+	outcome := GuessOutcome{Letter: letter}
+	outcome.Positions, err = game.GuessLetter(letter)
+	switch {
+	case errors.Is(err, ErrLetterAlreadyGuessed):
+		outcome.Result = GuessRepeat
+	case err != nil:
+		return GuessOutcome{}, err
+	case len(outcome.Positions) > 0:
+		// Правильный ответ
+		outcome.Result = GuessCorrect
+		outcome.Points = 10 // Очки за правильную букву
 		if game.IsWordGuessed() {
-			game.Score += 50 // Бонус за завершение слова
-			player.Score += 50
-			return true, "Congratulations! You guessed the word: " + game.Word, nil
+			outcome.Result = GuessWon
+			outcome.Points += 50 // Бонус за завершение слова
+		}
+	default:
+		// Неправильный ответ
+		game.AttemptsLeft--
+		outcome.Result = GuessWrong
+		outcome.Points = -5 // Штраф за неправильный ответ
+		if game.AttemptsLeft <= 0 {
+			outcome.Result = GuessLost
 		}
+	}
+
+	gsm.addScore(game, username, outcome.Points)
+	return gsm.finishOutcome(game, outcome), nil
+}
 
-		return true, "Correct guess!", nil
+// MakeWordGuess проверяет попытку отгадать слово целиком.
+// Верное слово приносит большой бонус, неверное отнимает сразу несколько попыток.
+func (gsm *GameStateManager) MakeWordGuess(player *Player, word string) (GuessOutcome, error) {
+	gsm.mu.Lock()
+	defer gsm.mu.Unlock()
+	username := PlayerUsername(player.Username)
+	game, err := gsm.playableGame(username)
+	if err != nil {
+		return GuessOutcome{}, err
 	}
 
-	// Неправильный ответ
-	game.AttemptsLeft--
-	game.Score -= 5 // Штраф за неправильный ответ
-	player.Score -= 5
-	if game.Score < 0 {
-		game.Score = 0 // Убедиться, что счет не становится отрицательным
+	outcome := GuessOutcome{Word: word}
+	if positions, ok := game.GuessWord(word); ok {
+		outcome.Result = GuessWon
+		outcome.Positions = positions
+		outcome.Points = wordGuessBonus
+	} else {
+		game.AttemptsLeft = max(game.AttemptsLeft-wordGuessAttemptCost, 0)
+		outcome.Result = GuessWrong
+		outcome.Points = -wordGuessPenalty
+		if game.AttemptsLeft <= 0 {
+			outcome.Result = GuessLost
+		}
 	}
 
-	// Проверить, закончились ли попытки
-	if game.AttemptsLeft <= 0 {
-		return false, "Game Over! The word was: " + game.Word, nil
+	gsm.addScore(game, username, outcome.Points)
+	return gsm.finishOutcome(game, outcome), nil
+}
+
+// finishOutcome дополняет исход состоянием игры после хода и запускает часы следующего хода.
+// Вызывается под блокировкой.
+func (gsm *GameStateManager) finishOutcome(game *Game, outcome GuessOutcome) GuessOutcome {
+	if outcome.Counts() {
+		game.TurnStarted = time.Now()
 	}
+	outcome.AttemptsLeft = game.AttemptsLeft
+	outcome.WordProgress = game.DisplayWord()
+	if outcome.IsGameOver() {
+		outcome.Answer = game.Word
+	}
+	return outcome
+}
 
-	return false, "Wrong guess!", nil
+// addScore начисляет очки игре игрока, а в общем слове — лично игроку.
+// Счёт не становится отрицательным.
+func (gsm *GameStateManager) addScore(game *Game, username PlayerUsername, delta int) {
+	if gsm.turns != nil {
+		gsm.scores[username] = max(gsm.scores[username]+delta, 0)
+		return
+	}
+	game.Score = max(game.Score+delta, 0)
 }
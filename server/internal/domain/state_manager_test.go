@@ -0,0 +1,196 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSharedGameTurns(t *testing.T) {
+	gsm := NewGameStateManager()
+	gsm.AddSharedGame("кот", []PlayerUsername{"alice", "bob"}, 3)
+	alice, bob := NewPlayer(nil, "alice", 0), NewPlayer(nil, "bob", 0)
+	active := func(PlayerUsername) bool { return true }
+
+	if _, err := gsm.MakeGuess(bob, 'к'); !errors.Is(err, ErrNotYourTurn) {
+		t.Fatalf("expected ErrNotYourTurn, got %v", err)
+	}
+	if outcome, err := gsm.MakeGuess(alice, 'к'); err != nil || outcome.Result != GuessCorrect {
+		t.Fatalf("expected correct guess, got %+v, %v", outcome, err)
+	}
+	if next, ok := gsm.AdvanceTurn(active); !ok || next != "bob" {
+		t.Fatalf("expected turn to pass to bob, got %q", next)
+	}
+
+	// Ошибка bob тратит общий запас попыток, но не трогает очки alice
+	if outcome, err := gsm.MakeGuess(bob, 'ы'); err != nil || outcome.Result != GuessWrong {
+		t.Fatalf("expected wrong guess, got %+v, %v", outcome, err)
+	}
+	aliceState, _ := gsm.GetState("alice")
+	bobState, _ := gsm.GetState("bob")
+	if aliceState.AttemptsLeft != 2 || bobState.AttemptsLeft != 2 {
+		t.Errorf("attempts must be shared, got %d and %d", aliceState.AttemptsLeft, bobState.AttemptsLeft)
+	}
+	if aliceState.Score != 10 || bobState.Score != 0 {
+		t.Errorf("scores must be personal, got alice=%d bob=%d", aliceState.Score, bobState.Score)
+	}
+	if aliceState.WordProgress != bobState.WordProgress {
+		t.Errorf("word must be shared, got %q and %q", aliceState.WordProgress, bobState.WordProgress)
+	}
+
+	// Отключённый игрок пропускается
+	next, ok := gsm.AdvanceTurn(func(username PlayerUsername) bool { return username != "alice" })
+	if !ok || next != "bob" {
+		t.Fatalf("expected turn to stay with bob, got %q", next)
+	}
+}
+
+func TestFinishRace(t *testing.T) {
+	gsm := NewGameStateManager()
+	for _, username := range []PlayerUsername{"alice", "bob", "carol"} {
+		gsm.AddGame("cat", username, 5)
+	}
+	alice, bob := NewPlayer(nil, "alice", 0), NewPlayer(nil, "bob", 0)
+
+	gsm.MakeGuess(bob, 'c')
+	for _, letter := range "cat" {
+		gsm.MakeGuess(alice, letter)
+	}
+	if _, ok := gsm.FinishRace("bob"); ok {
+		t.Fatalf("bob has not guessed the word and cannot win")
+	}
+
+	result, ok := gsm.FinishRace("alice")
+	if !ok || result.Winner != "alice" {
+		t.Fatalf("expected alice to win, got %+v", result)
+	}
+	places := make([]PlayerUsername, 0, len(result.Standings))
+	for _, placement := range result.Standings {
+		places = append(places, placement.Username)
+	}
+	if len(places) != 3 || places[0] != "alice" || places[1] != "bob" || places[2] != "carol" {
+		t.Fatalf("unexpected standings: %v", places)
+	}
+
+	// Игры соперников закрыты, повторной победы нет
+	if _, err := gsm.MakeGuess(bob, 'a'); err == nil {
+		t.Errorf("expected bob's game to be closed")
+	}
+	if state, _ := gsm.GetState("carol"); !state.IsGameOver || state.IsWon {
+		t.Errorf("expected carol's game to be closed without a win, got %+v", state)
+	}
+	if _, ok := gsm.FinishRace("alice"); ok {
+		t.Errorf("race must finish only once")
+	}
+}
+
+func TestMakeGuessOutcome(t *testing.T) {
+	gsm := NewGameStateManager()
+	gsm.AddGame("кот", "alice", 2)
+	alice := NewPlayer(nil, "alice", 0)
+
+	outcome, err := gsm.MakeGuess(alice, 'о')
+	if err != nil || outcome.Result != GuessCorrect || outcome.Points != 10 || len(outcome.Positions) != 1 || outcome.Positions[0] != 1 {
+		t.Fatalf("expected correct letter at position 1, got %+v, %v", outcome, err)
+	}
+	// Повтор не стоит ни попытки, ни очков
+	outcome, err = gsm.MakeGuess(alice, 'о')
+	if err != nil || outcome.Result != GuessRepeat || outcome.Points != 0 || outcome.AttemptsLeft != 2 {
+		t.Fatalf("expected free repeat, got %+v, %v", outcome, err)
+	}
+	if outcome, _ = gsm.MakeGuess(alice, 'ы'); outcome.Result != GuessWrong || outcome.Answer != "" {
+		t.Fatalf("expected wrong guess without answer, got %+v", outcome)
+	}
+	if outcome, _ = gsm.MakeGuess(alice, 'я'); outcome.Result != GuessLost || outcome.Answer != "кот" {
+		t.Fatalf("expected lost game with answer, got %+v", outcome)
+	}
+}
+
+func TestMakeWordGuess(t *testing.T) {
+	gsm := NewGameStateManager()
+	gsm.AddGame("golang", "alice", 5)
+	alice := NewPlayer(nil, "alice", 0)
+
+	outcome, err := gsm.MakeWordGuess(alice, "gopher")
+	if err != nil || outcome.Result != GuessWrong || outcome.Points != -wordGuessPenalty {
+		t.Fatalf("expected wrong word, got %+v, %v", outcome, err)
+	}
+	if state, _ := gsm.GetState("alice"); state.AttemptsLeft != 5-wordGuessAttemptCost || state.IsGameOver {
+		t.Fatalf("expected %d attempts left, got %+v", 5-wordGuessAttemptCost, state)
+	}
+
+	outcome, err = gsm.MakeWordGuess(alice, " GoLang ")
+	if err != nil || outcome.Result != GuessWon || outcome.Answer != "golang" {
+		t.Fatalf("expected correct word, got %+v, %v", outcome, err)
+	}
+	state, _ := gsm.GetState("alice")
+	if !state.IsWon || state.WordProgress != "golang" || state.Score != wordGuessBonus {
+		t.Errorf("unexpected state after correct word: %+v", state)
+	}
+}
+
+func TestRequestHint(t *testing.T) {
+	gsm := NewGameStateManager()
+	gsm.AddGame("cat", "alice", 5)
+	gsm.SetHints("alice", "pet that purrs", 2)
+	alice := NewPlayer(nil, "alice", 0)
+	gsm.MakeGuess(alice, 'a')
+
+	// Текст подсказки выдаётся один раз, дальше открываются буквы
+	hint, err := gsm.RequestHint(alice, HintDefinition)
+	if err != nil || hint.Kind != HintDefinition || hint.Text != "pet that purrs" {
+		t.Fatalf("expected definition hint, got %+v, %v", hint, err)
+	}
+	hint, err = gsm.RequestHint(alice, HintDefinition)
+	if err != nil || hint.Kind != HintLetter || hint.Letter != 'c' || hint.WordProgress != "ca_" {
+		t.Fatalf("expected letter hint, got %+v, %v", hint, err)
+	}
+	if state, _ := gsm.GetState("alice"); hint.HintsLeft != 0 || hint.Cost != letterHintCost || state.Score != 0 {
+		t.Errorf("unexpected hints left %d, cost %d or score %d", hint.HintsLeft, hint.Cost, state.Score)
+	}
+	if _, err := gsm.RequestHint(alice, HintLetter); !errors.Is(err, ErrNoHintsLeft) {
+		t.Errorf("expected ErrNoHintsLeft, got %v", err)
+	}
+
+	// Последнюю букву подсказка не открывает
+	gsm.SetHints("alice", "", 1)
+	if _, err := gsm.RequestHint(alice, HintLetter); !errors.Is(err, ErrHintUnavailable) {
+		t.Errorf("expected ErrHintUnavailable, got %v", err)
+	}
+}
+
+func TestMaskedState(t *testing.T) {
+	gsm := NewGameStateManager()
+	gsm.AddGame("абу-даби", "alice", 5)
+	alice := NewPlayer(nil, "alice", 0)
+	gsm.MakeGuess(alice, 'а')
+	gsm.MakeGuess(alice, 'я')
+
+	state, _ := gsm.GetState("alice")
+	masked := state.Masked()
+	if masked.WordProgress != "*__-_*__" {
+		t.Errorf("expected masked progress *__-_*__, got %s", masked.WordProgress)
+	}
+	if masked.Correct != nil || masked.Wrong != nil {
+		t.Errorf("expected guessed letters to be hidden, got %q and %q", string(masked.Correct), string(masked.Wrong))
+	}
+	if state.WordProgress != "а__-_а__" {
+		t.Errorf("masking must not change the original state, got %s", state.WordProgress)
+	}
+}
+
+func TestSharedGameWithoutPlayers(t *testing.T) {
+	gsm := NewGameStateManager()
+	gsm.AddSharedGame("кот", nil, 3)
+	if holder, ok := gsm.CurrentTurn(); ok {
+		t.Errorf("empty order must have no turn, got %q", holder)
+	}
+	if _, ok := gsm.AdvanceTurn(func(PlayerUsername) bool { return true }); ok {
+		t.Errorf("turn must not advance without players")
+	}
+
+	// Пустая очередь из снимка тоже не должна падать
+	empty := &turnOrder{}
+	if holder, ok := empty.advance(func(PlayerUsername) bool { return true }); ok || holder != "" {
+		t.Errorf("empty order advanced to %q", holder)
+	}
+}
@@ -0,0 +1,71 @@
+package domain
+
+import "time"
+
+// Штраф за пропущенный ход: как за неверную букву.
+const (
+	missedTurnPenalty      = 5
+	missedTurnAttemptsCost = 1
+)
+
+// TurnClocks возвращает, когда игроки, от которых сейчас ждут хода, получили ход.
+// В общем слове это только игрок, чей сейчас ход. Часы, которые ещё не шли
+// (новый раунд или игра из снимка), запускаются с now.
+func (gsm *GameStateManager) TurnClocks(now time.Time) map[PlayerUsername]time.Time {
+	gsm.mu.Lock()
+	defer gsm.mu.Unlock()
+
+	clocks := make(map[PlayerUsername]time.Time)
+	for username, game := range gsm.games {
+		if game.IsOver() || (gsm.turns != nil && gsm.turns.holder() != username) {
+			continue
+		}
+		if game.TurnStarted.IsZero() {
+			game.TurnStarted = now
+		}
+		clocks[username] = game.TurnStarted
+	}
+	return clocks
+}
+
+// MissTurn штрафует игрока, не успевшего сходить: списывает попытку и очки.
+// started — начало хода, по которому истекло время; если игрок успел сходить
+// после него, штраф не применяется и возвращается false. Часы хода запускаются заново.
+func (gsm *GameStateManager) MissTurn(username PlayerUsername, started time.Time) (GuessOutcome, bool) {
+	gsm.mu.Lock()
+	defer gsm.mu.Unlock()
+	game, err := gsm.playableGame(username)
+	if err != nil || !game.TurnStarted.Equal(started) {
+		return GuessOutcome{}, false
+	}
+
+	game.AttemptsLeft = max(game.AttemptsLeft-missedTurnAttemptsCost, 0)
+	outcome := GuessOutcome{Result: GuessWrong, Points: -missedTurnPenalty}
+	if game.AttemptsLeft <= 0 {
+		outcome.Result = GuessLost
+	}
+	gsm.addScore(game, username, outcome.Points)
+	return gsm.finishOutcome(game, outcome), true
+}
+
+// Forfeit закрывает все незавершённые игры, когда истекло время игры,
+// и возвращает исходы для игроков, которые их проиграли.
+func (gsm *GameStateManager) Forfeit() map[PlayerUsername]GuessOutcome {
+	gsm.mu.Lock()
+	defer gsm.mu.Unlock()
+
+	// В общем слове одна игра на всех, поэтому сначала собираем игроков, потом закрываем
+	var players []PlayerUsername
+	for username, game := range gsm.games {
+		if !game.IsOver() {
+			players = append(players, username)
+		}
+	}
+	outcomes := make(map[PlayerUsername]GuessOutcome, len(players))
+	for _, username := range players {
+		game := gsm.games[username]
+		game.Closed = true
+		outcomes[username] = GuessOutcome{Result: GuessLost, AttemptsLeft: game.AttemptsLeft, Answer: game.Word}
+	}
+	return outcomes
+}
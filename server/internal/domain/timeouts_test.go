@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMissTurn(t *testing.T) {
+	gsm := NewGameStateManager()
+	gsm.AddSharedGame("кот", []PlayerUsername{"alice", "bob"}, 2)
+	start := time.Now()
+
+	// В общем слове часы идут только у того, чей ход
+	clocks := gsm.TurnClocks(start)
+	if len(clocks) != 1 || !clocks["alice"].Equal(start) {
+		t.Fatalf("expected only alice's clock, got %v", clocks)
+	}
+	if _, ok := gsm.MissTurn("alice", start.Add(-time.Second)); ok {
+		t.Errorf("penalty must not apply to a turn that was already restarted")
+	}
+
+	outcome, ok := gsm.MissTurn("alice", start)
+	if !ok || outcome.Result != GuessWrong || outcome.AttemptsLeft != 1 {
+		t.Fatalf("expected missed turn penalty, got %+v, %v", outcome, ok)
+	}
+	if _, ok := gsm.MissTurn("alice", start); ok {
+		t.Errorf("the same turn must not be penalised twice")
+	}
+
+	gsm.AdvanceTurn(func(PlayerUsername) bool { return true })
+	bobStarted := gsm.TurnClocks(time.Now())["bob"]
+	if outcome, ok := gsm.MissTurn("bob", bobStarted); !ok || outcome.Result != GuessLost || outcome.Answer != "кот" {
+		t.Errorf("expected the last attempt to lose the game, got %+v, %v", outcome, ok)
+	}
+}
+
+func TestForfeit(t *testing.T) {
+	gsm := NewGameStateManager()
+	gsm.AddGame("кот", "alice", 5)
+	gsm.AddGame("пёс", "bob", 5)
+	if _, err := gsm.MakeWordGuess(NewPlayer(nil, "alice", 0), "кот"); err != nil {
+		t.Fatal(err)
+	}
+
+	outcomes := gsm.Forfeit()
+	if len(outcomes) != 1 || outcomes["bob"].Result != GuessLost || outcomes["bob"].Answer != "пёс" {
+		t.Fatalf("expected only bob to forfeit, got %+v", outcomes)
+	}
+	state, _ := gsm.GetState("bob")
+	if !state.IsGameOver || state.IsWon || state.AttemptsLeft != 5 {
+		t.Errorf("forfeited game must be over without spending attempts, got %+v", state)
+	}
+}
@@ -0,0 +1,28 @@
+package domain
+
+// turnOrder — очередь ходов в режиме общего слова.
+type turnOrder struct {
+	players []PlayerUsername
+	current int
+}
+
+// holder возвращает игрока, чей сейчас ход; пустую строку, если очередь пуста.
+func (t *turnOrder) holder() PlayerUsername {
+	if len(t.players) == 0 {
+		return ""
+	}
+	return t.players[t.current]
+}
+
+// advance передаёт ход следующему активному игроку.
+// Если активных игроков нет, ход остаётся у текущего.
+func (t *turnOrder) advance(isActive func(PlayerUsername) bool) (PlayerUsername, bool) {
+	for i := 1; i <= len(t.players); i++ {
+		next := (t.current + i) % len(t.players)
+		if isActive(t.players[next]) {
+			t.current = next
+			return t.players[next], true
+		}
+	}
+	return t.holder(), false
+}
@@ -3,6 +3,13 @@ package events
 type GameStartedEventPayload struct {
 	Category   string `json:"category"`   // Категория игры
 	Difficulty string `json:"difficulty"` // Сложность игры
+	Mode       string `json:"mode"`       // Режим игры
+	Round      int    `json:"round"`      // Номер раунда, начиная с 1
+	Rounds     int    `json:"rounds"`     // Всего раундов в матче
+}
+
+type TurnChangedEventPayload struct {
+	Username string `json:"username"` // Игрок, чей сейчас ход
 }
 
 type PlayerJoinedEventPayload struct {
@@ -20,3 +27,73 @@ type RoomHasBeenDeletedEventPayload struct {
 type RoomHasBeenUpdatedEventPayload struct {
 	RoomId string `json:"room_id"`
 }
+
+type RacePlacementPayload struct {
+	Username string `json:"username"`
+	Place    int    `json:"place"` // Место, начиная с 1
+	Bonus    int    `json:"bonus"` // Бонус за место
+	Score    int    `json:"score"` // Очки за игру с учётом бонуса
+}
+
+type RoundWonEventPayload struct {
+	Winner    string                 `json:"winner"`    // Игрок, первым отгадавший слово
+	Word      string                 `json:"word"`      // Загаданное слово
+	Standings []RacePlacementPayload `json:"standings"` // Места всех игроков
+}
+
+type RoundFinishedEventPayload struct {
+	Round  int            `json:"round"`  // Номер завершённого раунда
+	Rounds int            `json:"rounds"` // Всего раундов в матче
+	Scores map[string]int `json:"scores"` // Очки игроков за раунд
+	Totals map[string]int `json:"totals"` // Сумма очков за матч
+}
+
+type MatchAbortedEventPayload struct {
+	Round  int    `json:"round"`  // Последний сыгранный раунд
+	Reason string `json:"reason"` // Почему не удалось начать следующий раунд
+}
+
+type MatchStandingPayload struct {
+	Username string `json:"username"`
+	Place    int    `json:"place"` // Место, начиная с 1
+	Total    int    `json:"total"` // Сумма очков за матч
+}
+
+type MatchFinishedEventPayload struct {
+	Rounds      int                    `json:"rounds"`
+	Standings   []MatchStandingPayload `json:"standings"`    // Итоговые места
+	RoundScores []map[string]int       `json:"round_scores"` // Очки игроков по раундам
+}
+
+type GuessMadeEventPayload struct {
+	Username     string `json:"username"`                // Игрок, сделавший ход
+	Result       string `json:"result"`                  // correct, wrong, won или lost
+	Letter       string `json:"letter,omitempty"`        // Названная буква
+	Word         string `json:"word,omitempty"`          // Названное слово
+	Points       int    `json:"points"`                  // Изменение счёта за ход
+	Positions    []int  `json:"positions,omitempty"`     // Открытые позиции
+	AttemptsLeft int    `json:"attempts_left"`           // Остаток попыток игрока
+	WordProgress string `json:"word_progress,omitempty"` // Слово игрока после хода
+}
+
+type SpectatorJoinedEventPayload struct {
+	Username string `json:"username"` // Имя зрителя
+}
+
+type SpectatorLeftEventPayload struct {
+	Username string `json:"username"` // Имя зрителя
+}
+
+type TimerTickEventPayload struct {
+	GameSecondsLeft int            `json:"game_seconds_left,omitempty"` // До конца игры; нет, если время игры не ограничено
+	TurnSecondsLeft map[string]int `json:"turn_seconds_left,omitempty"` // До конца хода у игроков, от которых ждут хода
+}
+
+type TurnTimedOutEventPayload struct {
+	Username     string `json:"username"`         // Игрок, у которого истекло время
+	Reason       string `json:"reason"`           // turn — время хода, game — время игры
+	Result       string `json:"result"`           // wrong или lost
+	Points       int    `json:"points"`           // Штраф
+	AttemptsLeft int    `json:"attempts_left"`    // Остаток попыток игрока
+	Answer       string `json:"answer,omitempty"` // Загаданное слово, если игра проиграна
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"hangman/internal/domain"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	accountsBucket = []byte("accounts")
+	statsBucket    = []byte("stats")
+)
+
+// BoltPlayerRepository хранит учётные записи и статистику игроков в файле BoltDB.
+// Подключённые игроки по-прежнему хранятся в памяти.
+type BoltPlayerRepository struct {
+	*InMemoryPlayerRepository
+	db *bolt.DB
+}
+
+// NewBoltPlayerRepository открывает (или создаёт) файл хранилища.
+func NewBoltPlayerRepository(path string) (*BoltPlayerRepository, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{accountsBucket, statsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltPlayerRepository{
+		InMemoryPlayerRepository: NewPlayerRepository(),
+		db:                       db,
+	}, nil
+}
+
+// Close закрывает файл хранилища.
+func (r *BoltPlayerRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *BoltPlayerRepository) CreateAccount(account *domain.Account) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(accountsBucket)
+		if bucket.Get([]byte(account.Username)) != nil {
+			return errors.New("account already exists")
+		}
+
+		data, err := json.Marshal(account)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(account.Username), data)
+	})
+}
+
+// UpdateAccount перезаписывает существующую учётную запись.
+func (r *BoltPlayerRepository) UpdateAccount(account *domain.Account) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(accountsBucket)
+		if bucket.Get([]byte(account.Username)) == nil {
+			return errors.New("account not found")
+		}
+
+		data, err := json.Marshal(account)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(account.Username), data)
+	})
+}
+
+func (r *BoltPlayerRepository) GetAccount(username string) (*domain.Account, error) {
+	var account domain.Account
+	err := r.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(accountsBucket).Get([]byte(username))
+		if data == nil {
+			return errors.New("account not found")
+		}
+		return json.Unmarshal(data, &account)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func (r *BoltPlayerRepository) RecordGameResult(username string, score int, won bool) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(statsBucket)
+
+		stats := domain.PlayerStats{Username: username}
+		if data := bucket.Get([]byte(username)); data != nil {
+			if err := json.Unmarshal(data, &stats); err != nil {
+				return err
+			}
+		}
+
+		stats.RecordGame(score, won)
+
+		data, err := json.Marshal(stats)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(username), data)
+	})
+}
+
+func (r *BoltPlayerRepository) GetLeaderboard() ([]domain.PlayerStats, error) {
+	var leaderboard []domain.PlayerStats
+	err := r.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(statsBucket).ForEach(func(_, data []byte) error {
+			var stats domain.PlayerStats
+			if err := json.Unmarshal(data, &stats); err != nil {
+				return err
+			}
+			leaderboard = append(leaderboard, stats)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortLeaderboard(leaderboard)
+	return leaderboard, nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"hangman/internal/domain"
+	"path/filepath"
+	"testing"
+)
+
+func TestBoltPlayerRepositorySurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "players.db")
+
+	repo, err := NewBoltPlayerRepository(path)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	account, _ := domain.NewAccount("alice", "secret")
+	if err := repo.CreateAccount(account); err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	if err := repo.CreateAccount(account); err == nil {
+		t.Errorf("Expected duplicate account to be rejected")
+	}
+	repo.RecordGameResult("alice", 60, true)
+	repo.RecordGameResult("alice", 10, true)
+	repo.RecordGameResult("bob", 30, false)
+	repo.Close()
+
+	repo, err = NewBoltPlayerRepository(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	defer repo.Close()
+
+	stored, err := repo.GetAccount("alice")
+	if err != nil || !stored.CheckPassword("secret") {
+		t.Errorf("Expected account to survive reopen with the same password")
+	}
+
+	leaderboard, err := repo.GetLeaderboard()
+	if err != nil {
+		t.Fatalf("Failed to read leaderboard: %v", err)
+	}
+	if len(leaderboard) != 2 || leaderboard[0].Username != "alice" {
+		t.Fatalf("Expected alice to lead the leaderboard, got %+v", leaderboard)
+	}
+	alice := leaderboard[0]
+	if alice.Score != 70 || alice.GamesPlayed != 2 || alice.GamesWon != 2 || alice.BestStreak != 2 {
+		t.Errorf("Unexpected stats for alice: %+v", alice)
+	}
+	if leaderboard[1].CurrentStreak != 0 || leaderboard[1].GamesWon != 0 {
+		t.Errorf("Unexpected stats for bob: %+v", leaderboard[1])
+	}
+}
@@ -3,73 +3,86 @@ package repository
 import (
 	"errors"
 	"hangman/internal/domain"
+	"sort"
 	"sync"
 	"time"
 )
 
 type InMemoryPlayerRepository struct {
-	players map[domain.ClientKey]*domain.Player
-	mu      sync.RWMutex
+	players  map[string]*domain.Player
+	accounts map[string]*domain.Account
+	stats    map[string]*domain.PlayerStats
+	mu       sync.RWMutex
 }
 
 func NewPlayerRepository() *InMemoryPlayerRepository {
 	return &InMemoryPlayerRepository{
-		players: make(map[domain.ClientKey]*domain.Player),
+		players:  make(map[string]*domain.Player),
+		accounts: make(map[string]*domain.Account),
+		stats:    make(map[string]*domain.PlayerStats),
 	}
 }
 
-func (r *InMemoryPlayerRepository) AddPlayer(key domain.ClientKey, player *domain.Player) error {
+func (r *InMemoryPlayerRepository) CreateAccount(account *domain.Account) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	existingPlayer, exists := r.players[key]
-	if exists {
-		// Обновляем состояние существующего игрока
-		existingPlayer.IsConnected = true
-		existingPlayer.LastActive = time.Now()
-		return nil // Возвращаем true если пользователь уже был в комнате
+	if _, exists := r.accounts[account.Username]; exists {
+		return errors.New("account already exists")
 	}
 
-	// Добавляем нового игрока
-	r.players[key] = player
+	r.accounts[account.Username] = account
 	return nil
 }
 
-func (r *InMemoryPlayerRepository) PlayerExists(username string) bool {
-	r.mu.RLock() // Используем RLock, так как только читаем данные
+func (r *InMemoryPlayerRepository) GetAccount(username string) (*domain.Account, error) {
+	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	for key := range r.players {
-		if key.Username == username {
-			return true
-		}
+	account, exists := r.accounts[username]
+	if !exists {
+		return nil, errors.New("account not found")
 	}
 
-	return false
+	return account, nil
 }
 
-func (r *InMemoryPlayerRepository) RemovePlayer(key domain.ClientKey) error {
+// UpdateAccount перезаписывает существующую учётную запись.
+func (r *InMemoryPlayerRepository) UpdateAccount(account *domain.Account) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.players[key]; !exists {
-		return errors.New("player not found")
+	if _, exists := r.accounts[account.Username]; !exists {
+		return errors.New("account not found")
 	}
-
-	delete(r.players, key)
+	r.accounts[account.Username] = account
 	return nil
 }
 
-func (r *InMemoryPlayerRepository) GetPlayerByKey(key domain.ClientKey) (*domain.Player, error) {
+func (r *InMemoryPlayerRepository) AddPlayer(player *domain.Player) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	player, exists := r.players[key]
-	if !exists {
-		return nil, errors.New("player not found")
+	existingPlayer, exists := r.players[player.Username]
+	if exists {
+		// Обновляем состояние существующего игрока
+		existingPlayer.Conn = player.Conn
+		existingPlayer.IsConnected = true
+		existingPlayer.LastActive = time.Now()
+		return nil
 	}
 
-	return player, nil
+	// Добавляем нового игрока
+	r.players[player.Username] = player
+	return nil
+}
+
+func (r *InMemoryPlayerRepository) PlayerExists(username string) bool {
+	r.mu.RLock() // Используем RLock, так как только читаем данные
+	defer r.mu.RUnlock()
+
+	_, exists := r.players[username]
+	return exists
 }
 
 func (r *InMemoryPlayerRepository) GetAllPlayers() []*domain.Player {
@@ -98,12 +111,12 @@ func (r *InMemoryPlayerRepository) MonitorConnections(timeout time.Duration, ina
 	for range ticker.C {
 		r.mu.Lock()
 		var inactivePlayers []string
-		for key, player := range r.players {
+		for username, player := range r.players {
 			if time.Since(player.LastActive) > 3*time.Minute {
 				// Слишком долго без активности
 				player.IsConnected = false
 				inactivePlayers = append(inactivePlayers, player.Username)
-				delete(r.players, key)
+				delete(r.players, username)
 			}
 		}
 		r.mu.Unlock()
@@ -115,11 +128,11 @@ func (r *InMemoryPlayerRepository) MonitorConnections(timeout time.Duration, ina
 	}
 }
 
-func (r *InMemoryPlayerRepository) UpdatePlayerActivity(key domain.ClientKey) error {
+func (r *InMemoryPlayerRepository) UpdatePlayerActivity(username string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	player, exists := r.players[key]
+	player, exists := r.players[username]
 	if !exists {
 		return errors.New("player not found")
 	}
@@ -133,37 +146,63 @@ func (r *InMemoryPlayerRepository) GetPlayerByUsername(username string) (*domain
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	for key, player := range r.players {
-		if key.Username == username {
-			return player, nil
-		}
+	player, exists := r.players[username]
+	if !exists {
+		return nil, errors.New("player not found")
 	}
 
-	return nil, errors.New("player not found")
+	return player, nil
 }
 
 func (r *InMemoryPlayerRepository) RemovePlayerByUsername(username string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	for key := range r.players {
-		if key.Username == username {
-			delete(r.players, key)
-			return nil
-		}
+	if _, exists := r.players[username]; !exists {
+		return errors.New("player not found")
 	}
 
-	return errors.New("player not found")
+	delete(r.players, username)
+	return nil
 }
 
-func (r *InMemoryPlayerRepository) GetPlayerUsernamesAndScores() map[string]int {
+func (r *InMemoryPlayerRepository) RecordGameResult(username string, score int, won bool) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	userScores := make(map[string]int)
-	for key, player := range r.players {
-		userScores[key.Username] = player.Score
+	stats, exists := r.stats[username]
+	if !exists {
+		stats = &domain.PlayerStats{Username: username}
+		r.stats[username] = stats
+	}
+
+	stats.RecordGame(score, won)
+	return nil
+}
+
+func (r *InMemoryPlayerRepository) GetLeaderboard() ([]domain.PlayerStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	leaderboard := make([]domain.PlayerStats, 0, len(r.stats))
+	for _, stats := range r.stats {
+		leaderboard = append(leaderboard, *stats)
 	}
 
-	return userScores
+	sortLeaderboard(leaderboard)
+	return leaderboard, nil
+}
+
+// sortLeaderboard упорядочивает игроков по очкам, затем по победам и имени
+func sortLeaderboard(leaderboard []domain.PlayerStats) {
+	sort.Slice(leaderboard, func(i, j int) bool {
+		a, b := leaderboard[i], leaderboard[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.GamesWon != b.GamesWon {
+			return a.GamesWon > b.GamesWon
+		}
+		return a.Username < b.Username
+	})
 }
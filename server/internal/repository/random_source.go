@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// RandomSource — генератор случайных чисел под мьютексом.
+// *rand.Rand нельзя вызывать из нескольких горутин одновременно, а игры в комнатах начинаются параллельно.
+type RandomSource struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewRandomSource создаёт генератор с заданным зерном: одинаковое зерно даёт одинаковую последовательность.
+func NewRandomSource(seed int64) *RandomSource {
+	return &RandomSource{rnd: rand.New(rand.NewSource(seed))}
+}
+
+// Intn возвращает случайное число в [0, n)
+func (rs *RandomSource) Intn(n int) int {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.rnd.Intn(n)
+}
+
+// Shuffle перемешивает n элементов. swap вызывается под блокировкой генератора.
+func (rs *RandomSource) Shuffle(n int, swap func(i, j int)) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.rnd.Shuffle(n, swap)
+}
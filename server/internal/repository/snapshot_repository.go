@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"hangman/internal/domain"
+	"os"
+	"path/filepath"
+)
+
+// FileSnapshotRepository хранит снимок комнат в JSON-файле.
+type FileSnapshotRepository struct {
+	path string
+}
+
+func NewSnapshotRepository(path string) *FileSnapshotRepository {
+	return &FileSnapshotRepository{
+		path: path,
+	}
+}
+
+// Save атомарно перезаписывает файл снимка: сначала пишем во временный файл, затем переименовываем.
+func (r *FileSnapshotRepository) Save(rooms []domain.RoomSnapshot) error {
+	data, err := json.Marshal(rooms)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), r.path)
+}
+
+// Load читает снимок. Отсутствие файла не считается ошибкой.
+func (r *FileSnapshotRepository) Load() ([]domain.RoomSnapshot, error) {
+	data, err := os.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rooms []domain.RoomSnapshot
+	if err := json.Unmarshal(data, &rooms); err != nil {
+		return nil, err
+	}
+	return rooms, nil
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"hangman/internal/domain"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshotRepositorySave(t *testing.T) {
+	dir := t.TempDir()
+	repo := NewSnapshotRepository(filepath.Join(dir, "snapshot.json"))
+	if rooms, err := repo.Load(); err != nil || rooms != nil {
+		t.Fatalf("missing snapshot must load as empty, got %v, %v", rooms, err)
+	}
+
+	for _, id := range []string{"first", "second"} {
+		if err := repo.Save([]domain.RoomSnapshot{{ID: id, Owner: "alice"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rooms, err := repo.Load()
+	if err != nil || len(rooms) != 1 || rooms[0].ID != "second" {
+		t.Fatalf("expected the latest snapshot, got %+v, %v", rooms, err)
+	}
+
+	// Запись идёт через временный файл, который после переименования не остаётся
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("expected only snapshot.json, got %v", entries)
+	}
+
+	// Повреждённый снимок не загружается молча
+	if err := os.WriteFile(filepath.Join(dir, "snapshot.json"), []byte("[{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Load(); err == nil {
+		t.Errorf("expected an error for a corrupt snapshot")
+	}
+}
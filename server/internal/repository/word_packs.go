@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hangman/internal/domain"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// packLoader читает набор слов. name — имя файла без расширения.
+type packLoader func(name string, r io.Reader) (map[string]Category, error)
+
+// Поддерживаемые форматы наборов слов по расширению файла
+var packLoaders = map[string]packLoader{
+	".json": loadJSONPack,
+	".csv":  loadCSVPack,
+	".txt":  loadTextPack,
+}
+
+// isWordPack проверяет, что файл похож на набор слов по расширению
+func isWordPack(path string) bool {
+	_, ok := packLoaders[strings.ToLower(filepath.Ext(path))]
+	return ok
+}
+
+// loadWordPacks загружает набор слов из файла или все наборы из каталога и объединяет их категории.
+// Файлы каталога читаются по алфавиту, файлы других форматов пропускаются.
+func loadWordPacks(path string) (map[string]Category, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		files = files[:0]
+		for _, entry := range entries {
+			if !entry.IsDir() && isWordPack(entry.Name()) {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	categories := make(map[string]Category)
+	for _, file := range files {
+		pack, err := loadPackFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+		for name, category := range pack {
+			if err := mergeCategory(categories, name, category); err != nil {
+				return nil, fmt.Errorf("%s: %w", filepath.Base(file), err)
+			}
+		}
+	}
+	if len(categories) == 0 {
+		return nil, errors.New("no categories found in word packs")
+	}
+	return categories, nil
+}
+
+func loadPackFile(path string) (map[string]Category, error) {
+	load, ok := packLoaders[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return nil, errors.New("unsupported word pack format")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return load(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), file)
+}
+
+// mergeCategory добавляет слова категории из очередного набора.
+// Алфавит одной категории во всех наборах должен совпадать.
+func mergeCategory(categories map[string]Category, name string, category Category) error {
+	if category.Alphabet == "" {
+		category.Alphabet = domain.DefaultLocale
+	}
+	existing, ok := categories[name]
+	if !ok {
+		categories[name] = category
+		return nil
+	}
+	if existing.Alphabet != category.Alphabet || existing.FoldYo != category.FoldYo {
+		return fmt.Errorf("category %s: alphabet differs from another word pack", name)
+	}
+	existing.Words = append(existing.Words, category.Words...)
+	categories[name] = existing
+	return nil
+}
+
+// loadJSONPack читает набор в формате words.json: объект категорий
+func loadJSONPack(_ string, r io.Reader) (map[string]Category, error) {
+	var pack map[string]Category
+	if err := json.NewDecoder(r).Decode(&pack); err != nil {
+		return nil, err
+	}
+	return pack, nil
+}
+
+// loadCSVPack читает набор в CSV. Первая строка — заголовок: обязательны столбцы category и word,
+// необязательны hint, difficulty, language, tags (через «;»), alphabet и fold_yo.
+// Прочие столбцы пропускаются, строки с # — комментарии.
+func loadCSVPack(_ string, r io.Reader) (map[string]Category, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, required := range []string{"category", "word"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing column %q", required)
+		}
+	}
+	field := func(record []string, column string) string {
+		if i, ok := columns[column]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	pack := make(map[string]Category)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return pack, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		name := field(record, "category")
+		if name == "" {
+			return nil, fmt.Errorf("line %d: empty category", line)
+		}
+		foldYo := false
+		if value := field(record, "fold_yo"); value != "" {
+			if foldYo, err = strconv.ParseBool(value); err != nil {
+				return nil, fmt.Errorf("line %d: invalid fold_yo %q", line, value)
+			}
+		}
+		entry := WordEntry{Word: field(record, "word"), Hint: field(record, "hint"), Language: field(record, "language")}
+		if value := field(record, "difficulty"); value != "" {
+			if entry.Difficulty, err = strconv.Atoi(value); err != nil {
+				return nil, fmt.Errorf("line %d: invalid difficulty %q", line, value)
+			}
+		}
+		if value := field(record, "tags"); value != "" {
+			entry.Tags = strings.Split(value, ";")
+		}
+		category := Category{
+			Alphabet: field(record, "alphabet"),
+			FoldYo:   foldYo,
+			Words:    []WordEntry{entry},
+		}
+		if err := mergeCategory(pack, name, category); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+}
+
+// loadTextPack читает слова по одному в строке; категория называется по имени файла.
+// Пустые строки пропускаются, строки с # — комментарии. В комментариях можно указать
+// алфавит категории: «# alphabet: en» и «# fold_yo: true».
+func loadTextPack(name string, r io.Reader) (map[string]Category, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	category := Category{}
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if comment, ok := strings.CutPrefix(line, "#"); ok {
+			key, value, _ := strings.Cut(comment, ":")
+			switch strings.TrimSpace(key) {
+			case "alphabet":
+				category.Alphabet = strings.TrimSpace(value)
+			case "fold_yo":
+				if category.FoldYo, err = strconv.ParseBool(strings.TrimSpace(value)); err != nil {
+					return nil, fmt.Errorf("line %d: invalid fold_yo", i+1)
+				}
+			}
+			continue
+		}
+		category.Words = append(category.Words, WordEntry{Word: line})
+	}
+	return map[string]Category{name: category}, nil
+}
+
+// MaxWordDifficulty — наибольшая оценка сложности слова.
+const MaxWordDifficulty = 10
+
+// normalizeEntry приводит слово к виду, в котором его хранит репозиторий, и проверяет метаданные.
+// Язык по умолчанию — алфавит категории, теги приводятся к нижнему регистру.
+func normalizeEntry(alphabet *domain.Alphabet, entry WordEntry) (WordEntry, error) {
+	entry.Word = alphabet.NormalizeWord(strings.TrimSpace(entry.Word))
+	if err := validateWord(alphabet, entry.Word); err != nil {
+		return entry, err
+	}
+	if entry.Difficulty < 0 || entry.Difficulty > MaxWordDifficulty {
+		return entry, fmt.Errorf("word %q: difficulty must be between 1 and %d", entry.Word, MaxWordDifficulty)
+	}
+	entry.Hint = strings.TrimSpace(entry.Hint)
+	if entry.Language = strings.TrimSpace(entry.Language); entry.Language == "" {
+		entry.Language = alphabet.Locale
+	}
+	tags := make([]string, 0, len(entry.Tags))
+	for _, tag := range entry.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	entry.Tags = tags
+	return entry, nil
+}
+
+// validateWord проверяет, что слово можно отгадать: в нём есть буквы,
+// и все они входят в алфавит категории.
+func validateWord(alphabet *domain.Alphabet, word string) error {
+	letters := 0
+	for _, r := range word {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		if _, err := alphabet.NormalizeLetter(r); err != nil {
+			return fmt.Errorf("word %q: letter %q is not in the %s alphabet", word, r, alphabet.Locale)
+		}
+		letters++
+	}
+	if letters == 0 {
+		return fmt.Errorf("word %q has no letters", word)
+	}
+	return nil
+}
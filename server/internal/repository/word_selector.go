@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"slices"
+	"strings"
+)
+
+// playerHistorySize — сколько последних слов игрока не выдаются ему повторно, пока есть другие
+const playerHistorySize = 20
+
+// wordBag — перемешанные слова комнаты, которые ещё не выдавались.
+// Когда мешок пустеет, он наполняется заново в новом порядке.
+type wordBag struct {
+	generation int
+	entries    []WordEntry
+}
+
+// NextEntry выбирает слово для игроков комнаты без повторов. Слова комнаты раздаются
+// из мешка, пока все слова категории под filter не будут выданы, а из мешка берётся первое слово,
+// которое никому из players недавно не попадалось. Выбранное слово запоминается в истории игроков.
+func (ws *WordsRepository) NextEntry(roomID string, players []string, category string, filter WordFilter) (WordEntry, error) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	key := roomID + "\x00" + category + "\x00" + filter.Difficulty + "\x00" + filter.Tag
+	bag, exists := ws.bags[key]
+	if !exists || bag.generation != ws.generation || len(bag.entries) == 0 {
+		entries, err := ws.candidates(category, filter)
+		if err != nil {
+			return WordEntry{}, err
+		}
+		bag = &wordBag{generation: ws.generation, entries: slices.Clone(entries)}
+		ws.randomSource.Shuffle(len(bag.entries), func(i, j int) {
+			bag.entries[i], bag.entries[j] = bag.entries[j], bag.entries[i]
+		})
+		ws.bags[key] = bag
+	}
+
+	// Если недавними оказались все слова мешка, повтор неизбежен — берём первое
+	index := slices.IndexFunc(bag.entries, func(entry WordEntry) bool {
+		return !ws.recentlyPlayed(players, entry.Word)
+	})
+	index = max(index, 0)
+	entry := bag.entries[index]
+	bag.entries = slices.Delete(bag.entries, index, index+1)
+
+	for _, player := range players {
+		history := append(ws.history[player], entry.Word)
+		if len(history) > playerHistorySize {
+			history = history[len(history)-playerHistorySize:]
+		}
+		ws.history[player] = history
+	}
+	return entry, nil
+}
+
+// recentlyPlayed проверяет, попадалось ли слово кому-то из игроков недавно. Вызывается под блокировкой.
+func (ws *WordsRepository) recentlyPlayed(players []string, word string) bool {
+	for _, player := range players {
+		if slices.Contains(ws.history[player], word) {
+			return true
+		}
+	}
+	return false
+}
+
+// ForgetRoom освобождает мешки слов удалённой комнаты. История игроков сохраняется.
+func (ws *WordsRepository) ForgetRoom(roomID string) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	prefix := roomID + "\x00"
+	for key := range ws.bags {
+		if strings.HasPrefix(key, prefix) {
+			delete(ws.bags, key)
+		}
+	}
+}
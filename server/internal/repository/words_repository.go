@@ -3,63 +3,324 @@ package repository
 import (
 	"encoding/json"
 	"errors"
-	"math/rand"
+	"fmt"
+	"hangman/internal/domain"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
+
+	"github.com/fsnotify/fsnotify"
 )
 
+// reloadDelay — пауза после изменения файлов перед перезагрузкой слов
+const reloadDelay = 500 * time.Millisecond
+
+// WordEntry — слово из банка слов вместе с подсказкой и метаданными.
+// В файле слово задаётся строкой или объектом {"word": ..., "hint": ..., "difficulty": ...}.
+type WordEntry struct {
+	Word       string   `json:"word"`
+	Hint       string   `json:"hint,omitempty"`
+	Difficulty int      `json:"difficulty,omitempty"` // Сложность от 1 до 10; 0 — не оценена
+	Language   string   `json:"language,omitempty"`   // Язык слова; по умолчанию — алфавит категории
+	Tags       []string `json:"tags,omitempty"`
+}
+
+// Полосы сложности слов для уровней сложности комнаты
+var difficultyBands = map[string][2]int{
+	"easy":   {1, 3},
+	"medium": {4, 7},
+	"hard":   {8, 10},
+}
+
+// ErrNoMatchingWords возвращается, если в категории нет слов под условия выбора.
+var ErrNoMatchingWords = errors.New("no words match the filter")
+
+// WordFilter — условия выбора слова. Пустое поле не ограничивает выбор.
+type WordFilter struct {
+	Difficulty string // Полоса сложности: easy, medium или hard
+	Tag        string
+}
+
+// matches проверяет слово на условия выбора. Слова без оценки подходят под любую полосу.
+func (f WordFilter) matches(entry WordEntry) bool {
+	if band, ok := difficultyBands[f.Difficulty]; ok && entry.Difficulty != 0 &&
+		(entry.Difficulty < band[0] || entry.Difficulty > band[1]) {
+		return false
+	}
+	if f.Tag == "" {
+		return true
+	}
+	tag := strings.ToLower(f.Tag)
+	for _, t := range entry.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// UnmarshalJSON принимает обе формы записи слова.
+func (e *WordEntry) UnmarshalJSON(data []byte) error {
+	var word string
+	if err := json.Unmarshal(data, &word); err == nil {
+		*e = WordEntry{Word: word}
+		return nil
+	}
+	type plain WordEntry
+	var entry plain
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return err
+	}
+	if entry.Word == "" {
+		return errors.New("word entry without word")
+	}
+	*e = WordEntry(entry)
+	return nil
+}
+
+// Category — категория банка слов вместе с алфавитом, на котором записаны слова.
+// Категория может быть задана и просто списком слов — тогда используется алфавит по умолчанию.
+type Category struct {
+	Alphabet string      `json:"alphabet"`
+	FoldYo   bool        `json:"fold_yo"` // Считать «ё» и «е» одной буквой
+	Words    []WordEntry `json:"words"`
+}
+
+// UnmarshalJSON принимает обе формы записи категории.
+func (c *Category) UnmarshalJSON(data []byte) error {
+	var words []WordEntry
+	if err := json.Unmarshal(data, &words); err == nil {
+		*c = Category{Words: words}
+		return nil
+	}
+	type plain Category
+	var category plain
+	if err := json.Unmarshal(data, &category); err != nil {
+		return err
+	}
+	*c = Category(category)
+	return nil
+}
+
 // WordsRepository отвечает за хранение слов для игры.
+// Слова загружаются из набора или каталога наборов и могут перечитываться на ходу.
 type WordsRepository struct {
-	categories   map[string][]string
-	randomSource *rand.Rand // Генератор случайных чисел
+	path         string
+	mu           sync.RWMutex
+	categories   map[string][]WordEntry
+	alphabets    map[string]*domain.Alphabet
+	generation   int                 // Номер загрузки слов; мешки прежних загрузок собираются заново
+	bags         map[string]*wordBag // Невыданные слова комнат
+	history      map[string][]string // Последние слова игроков, новые в конце
+	randomSource domain.IRandomSource
 }
 
-// NewWordsRepository создает новый экземпляр WordsRepository и загружает слова из JSON-файла.
-func NewWordsRepository(filePath string) (*WordsRepository, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
+// NewWordsRepository создает новый экземпляр WordsRepository и загружает слова.
+// path — файл набора слов или каталог с наборами в форматах JSON, CSV и TXT.
+func NewWordsRepository(path string) (*WordsRepository, error) {
+	return NewWordsRepositoryWithRandom(path, NewRandomSource(time.Now().UnixNano()))
+}
+
+// NewSeededWordsRepository создаёт репозиторий с заданным зерном генератора:
+// при одинаковом зерне слова выдаются в одном и том же порядке. Нужен для тестов.
+func NewSeededWordsRepository(path string, seed int64) (*WordsRepository, error) {
+	return NewWordsRepositoryWithRandom(path, NewRandomSource(seed))
+}
+
+// NewWordsRepositoryWithRandom создаёт репозиторий, выбирающий слова с помощью random.
+// random вызывается из разных горутин и должен быть потокобезопасным.
+func NewWordsRepositoryWithRandom(path string, random domain.IRandomSource) (*WordsRepository, error) {
+	ws := &WordsRepository{
+		path:         path,
+		bags:         make(map[string]*wordBag),
+		history:      make(map[string][]string),
+		randomSource: random,
+	}
+	if err := ws.Reload(); err != nil {
 		return nil, err
 	}
-	defer file.Close()
+	return ws, nil
+}
 
-	var categories map[string][]string
-	if err := json.NewDecoder(file).Decode(&categories); err != nil {
-		return nil, err
+// Reload перечитывает наборы слов. Если наборы не прошли проверку, остаются прежние слова.
+// Идущие игры не затрагиваются: розданные слова хранятся в самих играх.
+func (ws *WordsRepository) Reload() error {
+	categories, err := loadWordPacks(ws.path)
+	if err != nil {
+		return err
+	}
+
+	words := make(map[string][]WordEntry, len(categories))
+	alphabets := make(map[string]*domain.Alphabet, len(categories))
+	for name, category := range categories {
+		alphabet, err := domain.NewAlphabet(category.Alphabet, category.FoldYo)
+		if err != nil {
+			return fmt.Errorf("category %s: %w", name, err)
+		}
+		// Слова хранятся в том же виде, к которому приводятся догадки игроков
+		seen := make(map[string]bool, len(category.Words))
+		entries := make([]WordEntry, 0, len(category.Words))
+		for _, entry := range category.Words {
+			entry, err := normalizeEntry(alphabet, entry)
+			if err != nil {
+				return fmt.Errorf("category %s: %w", name, err)
+			}
+			if seen[entry.Word] {
+				continue // Повтор из другого набора
+			}
+			seen[entry.Word] = true
+			entries = append(entries, entry)
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("category %s has no words", name)
+		}
+		words[name] = entries
+		alphabets[name] = alphabet
+	}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	// Алфавиты удалённых категорий нужны, чтобы доиграть уже начатые в них игры
+	for name, alphabet := range ws.alphabets {
+		if _, exists := alphabets[name]; !exists {
+			alphabets[name] = alphabet
+		}
 	}
+	ws.categories = words
+	ws.alphabets = alphabets
+	ws.generation++
+	return nil
+}
 
-	if len(categories) == 0 {
-		return nil, errors.New("no categories found in the file")
+// Watch перечитывает наборы слов при изменении их файлов и сообщает результат в onReload.
+// Изменения копятся reloadDelay, чтобы не перечитывать файл на каждую запись редактора.
+func (ws *WordsRepository) Watch(onReload func(error)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	// Следим за каталогом: редакторы часто сохраняют файл через переименование
+	dir := ws.path
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
 	}
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	go func() {
+		defer watcher.Close()
+		var reload <-chan time.Time
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if ws.watches(event.Name) {
+					reload = time.After(reloadDelay)
+				}
+			case <-reload:
+				reload = nil
+				onReload(ws.Reload())
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				onReload(err)
+			}
+		}
+	}()
+	return nil
+}
 
-	return &WordsRepository{
-		categories:   categories,
-		randomSource: rand.New(rand.NewSource(time.Now().UnixNano())),
-	}, nil
+// watches проверяет, что изменённый файл относится к наборам слов репозитория
+func (ws *WordsRepository) watches(name string) bool {
+	if info, err := os.Stat(ws.path); err == nil && info.IsDir() {
+		return isWordPack(name)
+	}
+	return filepath.Clean(name) == filepath.Clean(ws.path)
 }
 
-// GetRandomWord возвращает случайное слово из указанной категории.
-func (ws *WordsRepository) GetRandomWord(category string) (string, error) {
-	words, err := ws.GetAllWords(category)
+// GetRandomWord возвращает случайное слово из указанной категории, подходящее под filter.
+func (ws *WordsRepository) GetRandomWord(category string, filter WordFilter) (string, error) {
+	entry, err := ws.GetRandomEntry(category, filter)
 	if err != nil {
 		return "", err
 	}
+	return entry.Word, nil
+}
+
+// GetRandomEntry возвращает случайное слово из указанной категории вместе с подсказкой и метаданными.
+// Если под filter не подходит ни одно слово категории, возвращается ErrNoMatchingWords.
+func (ws *WordsRepository) GetRandomEntry(category string, filter WordFilter) (WordEntry, error) {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	entries, err := ws.candidates(category, filter)
+	if err != nil {
+		return WordEntry{}, err
+	}
 
-	index := ws.randomSource.Intn(len(words))
+	index := ws.randomSource.Intn(len(entries))
 
-	return words[index], nil
+	return entries[index], nil
+}
+
+// candidates возвращает слова категории, подходящие под filter. Вызывается под блокировкой.
+func (ws *WordsRepository) candidates(category string, filter WordFilter) ([]WordEntry, error) {
+	entries, ok := ws.categories[category]
+	if !ok || len(entries) == 0 {
+		return nil, errors.New("category not found")
+	}
+	if filter == (WordFilter{}) {
+		return entries, nil
+	}
+	matching := make([]WordEntry, 0, len(entries))
+	for _, entry := range entries {
+		if filter.matches(entry) {
+			matching = append(matching, entry)
+		}
+	}
+	if len(matching) == 0 {
+		return nil, ErrNoMatchingWords
+	}
+	return matching, nil
 }
 
 // GetAllWords возвращает все слова из указанной категории.
 func (ws *WordsRepository) GetAllWords(category string) ([]string, error) {
-	words, ok := ws.categories[category]
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	entries, ok := ws.categories[category]
 	if !ok {
 		return nil, errors.New("category not found")
 	}
+	words := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		words = append(words, entry.Word)
+	}
 	return words, nil
 }
 
+// GetAlphabet возвращает алфавит указанной категории.
+func (ws *WordsRepository) GetAlphabet(category string) (*domain.Alphabet, error) {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	alphabet, ok := ws.alphabets[category]
+	if !ok {
+		return nil, errors.New("category not found")
+	}
+	return alphabet, nil
+}
+
 // GetCategories возвращает список всех доступных категорий.
 func (ws *WordsRepository) GetCategories() []string {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
 	categories := make([]string, 0, len(ws.categories))
 	for category := range ws.categories {
 		categories = append(categories, category)
@@ -67,9 +328,19 @@ func (ws *WordsRepository) GetCategories() []string {
 	return categories
 }
 
-// GetAttempts рассчитывает количество попыток в зависимости от длины слова и уровня сложности.
-func (ws *WordsRepository) GetAttempts(word string, difficulty string) int {
-	length := len(word)
+// Попытки для слов с оценкой сложности: от maxRatedAttempts для сложности 1 до minRatedAttempts для 10
+const (
+	maxRatedAttempts = 10
+	minRatedAttempts = 5
+)
+
+// GetAttempts рассчитывает количество попыток на слово. Если у слова есть оценка сложности,
+// попытки считаются по ней, иначе — по длине слова и уровню сложности комнаты.
+func (ws *WordsRepository) GetAttempts(entry WordEntry, difficulty string) int {
+	if entry.Difficulty >= 1 && entry.Difficulty <= 10 {
+		return maxRatedAttempts - (entry.Difficulty-1)*(maxRatedAttempts-minRatedAttempts)/9
+	}
+	length := utf8.RuneCountInString(entry.Word)
 	baseAttempts := 0
 
 	switch difficulty {
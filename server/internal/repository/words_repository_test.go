@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"hangman/internal/domain"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRealWordsCanBeGuessed(t *testing.T) {
+	repo, err := NewWordsRepository("../../../assets/words.json")
+	if err != nil {
+		t.Fatalf("failed to load words: %v", err)
+	}
+
+	for _, category := range repo.GetCategories() {
+		alphabet, err := repo.GetAlphabet(category)
+		if err != nil {
+			t.Fatalf("category %s: %v", category, err)
+		}
+		words, _ := repo.GetAllWords(category)
+		for _, word := range words {
+			length := utf8.RuneCountInString(word)
+			game := domain.NewGame(word, repo.GetAttempts(WordEntry{Word: word}, "medium"))
+			if len(game.GuessedWord) != length || utf8.RuneCountInString(game.DisplayWord()) != length {
+				t.Errorf("%s: progress length does not match %d letters", word, length)
+				continue
+			}
+
+			// Угадываем все буквы слова по одной, как это делает игрок
+			for _, r := range word {
+				letter, err := alphabet.NormalizeLetter(r)
+				if err != nil {
+					continue // Дефисы и пробелы открыты заранее
+				}
+				if _, err := game.GuessLetter(letter); err != nil && err != domain.ErrLetterAlreadyGuessed {
+					t.Errorf("%s: unexpected error for %q: %v", word, letter, err)
+				}
+			}
+			if !game.IsWordGuessed() || game.DisplayWord() != word || len(game.Wrong) != 0 {
+				t.Errorf("%s: expected word to be guessed, got %s", word, game.DisplayWord())
+			}
+		}
+	}
+}
+
+func TestGetAttemptsCountsLetters(t *testing.T) {
+	repo := &WordsRepository{}
+
+	// «рига» — 4 буквы, но 8 байт
+	if attempts := repo.GetAttempts(WordEntry{Word: "рига"}, "medium"); attempts != 9 {
+		t.Errorf("expected 9 attempts for a short word, got %d", attempts)
+	}
+	if attempts := repo.GetAttempts(WordEntry{Word: "куала-лумпур"}, "medium"); attempts != 6 {
+		t.Errorf("expected 6 attempts for a long word, got %d", attempts)
+	}
+}
+
+func TestGetAttemptsUsesRating(t *testing.T) {
+	repo := &WordsRepository{}
+	tests := []struct {
+		entry    WordEntry
+		expected int
+	}{
+		{WordEntry{Word: "кот", Difficulty: 1}, 10},
+		{WordEntry{Word: "кот", Difficulty: 5}, 8},
+		{WordEntry{Word: "кот", Difficulty: 10}, 5},
+		// Оценка важнее длины: короткое, но трудное слово не получает бонус за длину
+		{WordEntry{Word: "рига", Difficulty: 9}, 6},
+		{WordEntry{Word: "куала-лумпур", Difficulty: 2}, 10},
+	}
+	for _, tt := range tests {
+		if attempts := repo.GetAttempts(tt.entry, "hard"); attempts != tt.expected {
+			t.Errorf("%s (%d): expected %d attempts, got %d", tt.entry.Word, tt.entry.Difficulty, tt.expected, attempts)
+		}
+	}
+}
+
+func TestWordPacksDirectory(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("base.json", `{"животные": {"alphabet": "ru", "fold_yo": true, "words": ["ёж", "кот"]}}`)
+	write("extra.csv", "category,word,hint,fold_yo\nживотные,Кот,,true\nживотные,пёс,Друг человека,true\n")
+	write("animals.txt", "# alphabet: en\ncat\n\ndog\n")
+	write("README.md", "не набор слов")
+
+	repo, err := NewWordsRepository(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	categories := repo.GetCategories()
+	sort.Strings(categories)
+	if strings.Join(categories, ",") != "animals,животные" {
+		t.Fatalf("unexpected categories: %v", categories)
+	}
+	// Категория из двух наборов объединяется, повторы отбрасываются, слова нормализуются
+	words, _ := repo.GetAllWords("животные")
+	if strings.Join(words, ",") != "еж,кот,пес" {
+		t.Errorf("unexpected merged words: %v", words)
+	}
+	if alphabet, _ := repo.GetAlphabet("animals"); alphabet.Locale != "en" {
+		t.Errorf("expected english alphabet for the text pack, got %s", alphabet.Locale)
+	}
+
+	// Набор с буквами не того алфавита отклоняется, прежние слова остаются
+	write("animals.txt", "# alphabet: en\nкот\n")
+	if err := repo.Reload(); err == nil {
+		t.Fatal("expected reload to fail on a word outside the alphabet")
+	}
+	if words, _ := repo.GetAllWords("animals"); len(words) != 2 {
+		t.Errorf("expected previous words after a failed reload, got %v", words)
+	}
+
+	// Удалённая категория пропадает из выдачи, но её алфавит остаётся для начатых игр
+	os.Remove(filepath.Join(dir, "animals.txt"))
+	if err := repo.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetRandomEntry("animals", WordFilter{}); err == nil {
+		t.Error("expected removed category to be unavailable for new games")
+	}
+	if _, err := repo.GetAlphabet("animals"); err != nil {
+		t.Errorf("expected alphabet of the removed category to stay: %v", err)
+	}
+}
+
+func TestWordMetadataFilter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.json")
+	content := `{
+  "старые": ["кот", "пёс"],
+  "столицы": {"alphabet": "ru", "words": [
+    {"word": "рим", "difficulty": 2, "tags": ["Европа", "европа"]},
+    {"word": "осло", "difficulty": 9, "hint": "Столица Норвегии", "tags": ["европа"]},
+    {"word": "токио", "tags": ["азия"]}
+  ]}
+}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := NewWordsRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Старый формат — просто список слов — по-прежнему читается
+	if word, err := repo.GetRandomWord("старые", WordFilter{Difficulty: "hard"}); err != nil || word == "" {
+		t.Errorf("expected unrated word from a plain list, got %q, %v", word, err)
+	}
+	for i := 0; i < 20; i++ {
+		entry, err := repo.GetRandomEntry("столицы", WordFilter{Difficulty: "hard", Tag: "Европа"})
+		if err != nil || entry.Word != "осло" {
+			t.Fatalf("expected only the hard european word, got %+v, %v", entry, err)
+		}
+		if entry.Hint != "Столица Норвегии" || entry.Language != "ru" {
+			t.Errorf("metadata lost: %+v", entry)
+		}
+		// Слово без оценки подходит под любую полосу сложности
+		if entry, _ := repo.GetRandomEntry("столицы", WordFilter{Difficulty: "medium"}); entry.Word != "токио" {
+			t.Errorf("expected unrated word for the medium band, got %q", entry.Word)
+		}
+	}
+	if _, err := repo.GetRandomEntry("столицы", WordFilter{Tag: "африка"}); err != ErrNoMatchingWords {
+		t.Errorf("expected ErrNoMatchingWords, got %v", err)
+	}
+}
+
+func TestNextEntryDoesNotRepeat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(path, []byte("кот\nпёс\nёж\nлис\nвол\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	draw := func(repo *WordsRepository, roomID string, players ...string) string {
+		entry, err := repo.NextEntry(roomID, players, "words", WordFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return entry.Word
+	}
+
+	repo, err := NewSeededWordsRepository(path, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Пока мешок комнаты не опустел, слова не повторяются даже у разных игроков
+	seen := make(map[string]bool)
+	var order []string
+	for i := 0; i < 5; i++ {
+		word := draw(repo, "room", []string{"alice", "bob"}[i%2])
+		if seen[word] {
+			t.Fatalf("word %q repeated within the room: %v", word, order)
+		}
+		seen[word] = true
+		order = append(order, word)
+	}
+
+	// В новой комнате игроку сначала достаются слова, которых не было у него в истории
+	history := map[string]bool{order[0]: true, order[2]: true, order[4]: true}
+	for i := 0; i < 2; i++ {
+		if word := draw(repo, "other", "alice"); history[word] {
+			t.Errorf("alice got a recent word %q while others were available", word)
+		}
+	}
+
+	// Одинаковое зерно даёт одинаковый порядок
+	same, _ := NewSeededWordsRepository(path, 1)
+	for i, expected := range order {
+		if word := draw(same, "room", []string{"alice", "bob"}[i%2]); word != expected {
+			t.Fatalf("draw %d: expected %q with the same seed, got %q", i, expected, word)
+		}
+	}
+}
+
+func TestRandomSourceConcurrent(t *testing.T) {
+	first, second := NewRandomSource(7), NewRandomSource(7)
+	for i := 0; i < 10; i++ {
+		if a, b := first.Intn(100), second.Intn(100); a != b {
+			t.Fatalf("same seed gave %d and %d", a, b)
+		}
+	}
+
+	repo, err := NewWordsRepositoryWithRandom("../../../assets/words.json", first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Игры в комнатах начинаются одновременно; запускайте с -race
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := repo.GetRandomEntry("животные", WordFilter{}); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
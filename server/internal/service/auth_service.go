@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hangman/internal/domain"
+	"hangman/internal/errs"
+	tcp "hangman/pkg/tcp-server"
+	"hangman/pkg/utils"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type AuthService struct {
+	playerRepo domain.IPlayerRepository
+}
+
+func NewAuthService(playerRepo domain.IPlayerRepository) domain.IAuthService {
+	return &AuthService{
+		playerRepo: playerRepo,
+	}
+}
+
+// Register создаёт учётную запись и сразу авторизует соединение
+func (as *AuthService) Register(ctx context.Context, username, password string) (string, error) {
+	username = strings.TrimSpace(username)
+	if username == "" || password == "" {
+		return "", errs.NewError(tcp.StatusBadRequest, "username and password are required")
+	}
+
+	account, err := domain.NewAccount(username, password)
+	if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+		return "", errs.NewError(tcp.StatusBadRequest, "password must be at most 72 bytes")
+	}
+	if err != nil {
+		return "", errs.NewError(tcp.StatusInternalServerError, "failed to create account")
+	}
+	if err := as.playerRepo.CreateAccount(account); err != nil {
+		return "", errs.NewError(tcp.StatusConflict, "username is already taken")
+	}
+
+	return as.bindSession(ctx, username)
+}
+
+// Login проверяет пароль и привязывает сессию соединения к игроку
+func (as *AuthService) Login(ctx context.Context, username, password string) (string, error) {
+	account, err := as.playerRepo.GetAccount(username)
+	if err != nil || !account.CheckPassword(password) {
+		return "", errs.NewError(tcp.StatusUnauthorized, "invalid username or password")
+	}
+	if account.IsLegacyHash() {
+		// Пароль верный — заменяем старый хэш на bcrypt. Неудача не мешает входу
+		if err := as.upgradePassword(account, password); err != nil {
+			utils.NewCustomLogger(utils.LevelInfo).Error(fmt.Sprintf("Failed to rehash password of %s: %v", account.Username, err))
+		}
+	}
+
+	return as.bindSession(ctx, account.Username)
+}
+
+func (as *AuthService) upgradePassword(account *domain.Account, password string) error {
+	upgraded := *account
+	if err := upgraded.SetPassword(password); err != nil {
+		return err
+	}
+	return as.playerRepo.UpdateAccount(&upgraded)
+}
+
+func (as *AuthService) bindSession(ctx context.Context, username string) (string, error) {
+	session, ok := tcp.GetSession(ctx)
+	if !ok {
+		return "", errs.NewError(tcp.StatusInternalServerError, "session not found")
+	}
+	// Одно соединение — один игрок
+	if identity := session.Identity(); identity != "" && identity != username {
+		return "", errs.NewError(tcp.StatusConflict, "connection is already logged in as "+identity)
+	}
+
+	conn, _ := tcp.GetConn(ctx)
+	if err := as.playerRepo.AddPlayer(domain.NewPlayer(&conn, username, 0)); err != nil {
+		return "", err
+	}
+	session.Bind(username)
+
+	return session.Token(), nil
+}
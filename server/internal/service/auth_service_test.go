@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"hangman/internal/domain"
+	"hangman/internal/repository"
+	tcp "hangman/pkg/tcp-server"
+	"testing"
+)
+
+// sessionContext возвращает контекст нового соединения со своей сессией
+func sessionContext(t *testing.T) (context.Context, *tcp.Session) {
+	t.Helper()
+	session, err := tcp.NewSessionManager().Issue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tcp.SetSession(context.Background(), session), session
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+	players := repository.NewPlayerRepository()
+	auth := NewAuthService(players)
+
+	ctx, session := sessionContext(t)
+	token, err := auth.Register(ctx, "alice", "secret")
+	if err != nil || token != session.Token() || session.Identity() != "alice" {
+		t.Fatalf("register must bind the session, got %q, %v", token, err)
+	}
+
+	tests := []struct {
+		name     string
+		register bool
+		username string
+		password string
+		code     int32
+	}{
+		{name: "duplicate registration", register: true, username: "alice", password: "other", code: tcp.StatusConflict},
+		{name: "empty password", register: true, username: "bob", code: tcp.StatusBadRequest},
+		{name: "wrong password", username: "alice", password: "Secret", code: tcp.StatusUnauthorized},
+		{name: "unknown user", username: "carol", password: "secret", code: tcp.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, session := sessionContext(t)
+			var err error
+			if tt.register {
+				_, err = auth.Register(ctx, tt.username, tt.password)
+			} else {
+				_, err = auth.Login(ctx, tt.username, tt.password)
+			}
+			expectCode(t, err, tt.code)
+			if session.Identity() != "" {
+				t.Errorf("failed attempt must not bind the session, got %q", session.Identity())
+			}
+		})
+	}
+
+	// Соединение, уже вошедшее под одним именем, не может войти под другим
+	if _, err := auth.Register(ctx, "bob", "secret"); err == nil {
+		t.Fatalf("second account on the same connection must be rejected")
+	}
+	ctx, _ = sessionContext(t)
+	if _, err := auth.Login(ctx, "alice", "secret"); err != nil {
+		t.Fatalf("login with the right password failed: %v", err)
+	}
+}
+
+func TestLoginUpgradesLegacyHash(t *testing.T) {
+	players := repository.NewPlayerRepository()
+	sum := sha256.Sum256([]byte("00" + "secret"))
+	legacy := &domain.Account{Username: "alice", Salt: "00", PasswordHash: hex.EncodeToString(sum[:])}
+	if err := players.CreateAccount(legacy); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, _ := sessionContext(t)
+	if _, err := NewAuthService(players).Login(ctx, "alice", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	account, _ := players.GetAccount("alice")
+	if account.IsLegacyHash() || !account.CheckPassword("secret") {
+		t.Errorf("legacy hash must be replaced with bcrypt on login, got %+v", account)
+	}
+}
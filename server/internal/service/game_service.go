@@ -4,8 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"hangman/internal/domain"
+	"hangman/internal/errs"
 	"hangman/internal/events"
 	"hangman/internal/repository"
+	tcp "hangman/pkg/tcp-server"
+	"time"
 )
 
 type GameServiceImpl struct {
@@ -18,29 +21,178 @@ func NewGameService(repo *repository.WordsRepository) domain.IGameService {
 	}
 }
 
-// StartGame запускает игру
+// StartGame начинает новый матч с первого раунда
 func (gs *GameServiceImpl) StartGame(room *domain.Room) error {
-	//room.Lock()
-	//defer room.Unlock()
+	if len(room.GetAllPlayers()) == 0 {
+		return errNoPlayers
+	}
+	room.StartMatch()
+	return gs.startRound(room, 1)
+}
 
-	if room.StateManager == nil {
-		room.StateManager = domain.NewGameStateManager()
+// errNoPlayers — в комнате нет игроков, раздавать слова некому
+var errNoPlayers = errs.NewError(tcp.StatusConflict, "no players in the room")
+
+// startRound раздаёт слова и запускает раунд матча
+func (gs *GameServiceImpl) startRound(room *domain.Room, round int) error {
+	if len(room.GetAllPlayers()) == 0 {
+		return errNoPlayers
 	}
-	players := room.GetAllPlayers()
+	// Каждый раунд начинается с чистого состояния: режим комнаты мог измениться
+	stateManager := domain.NewGameStateManager()
+	hintLimit := domain.HintLimit(room.Difficulty)
 
-	for _, player := range players {
-		word, err := gs.wordsRepo.GetRandomWord(room.Category)
+	switch room.Mode {
+	case domain.ModeTurnBased:
+		// Одно слово и общий запас попыток и подсказок на всех
+		order := room.TurnOrder()
+		players := make([]string, len(order))
+		for i, username := range order {
+			players[i] = string(username)
+		}
+		entry, err := gs.nextEntry(room, players)
 		if err != nil {
 			return err
 		}
-		attemptsCount := gs.wordsRepo.GetAttempts(word, room.Difficulty)
-		room.StateManager.AddGame(word, domain.PlayerUsername(player), attemptsCount)
+		attemptsCount := gs.attempts(room, entry)
+		stateManager.AddSharedGame(entry.Word, order, attemptsCount)
+		if len(order) > 0 {
+			stateManager.SetHints(order[0], entry.Hint, hintLimit)
+		}
+	case domain.ModeRace:
+		// Одно слово, но у каждого своя игра
+		players := room.GetAllPlayers()
+		entry, err := gs.nextEntry(room, players)
+		if err != nil {
+			return err
+		}
+		attemptsCount := gs.attempts(room, entry)
+		for _, player := range players {
+			stateManager.AddGame(entry.Word, domain.PlayerUsername(player), attemptsCount)
+			stateManager.SetHints(domain.PlayerUsername(player), entry.Hint, hintLimit)
+		}
+	default:
+		// Слова игрокам раздаются из общего мешка комнаты, поэтому не совпадают
+		for _, player := range room.GetAllPlayers() {
+			entry, err := gs.nextEntry(room, []string{player})
+			if err != nil {
+				return err
+			}
+			attemptsCount := gs.attempts(room, entry)
+			stateManager.AddGame(entry.Word, domain.PlayerUsername(player), attemptsCount)
+			stateManager.SetHints(domain.PlayerUsername(player), entry.Hint, hintLimit)
+		}
 	}
-	room.SetState(domain.InProgress)
+
+	stateManager.SetCategory(room.Category)
+	room.BeginRound(round, stateManager)
+
 	// Уведомляем игроков о начале игры
 	err := room.NotifyPlayers("GameStarted", events.GameStartedEventPayload{
 		Category:   room.Category,
 		Difficulty: room.Difficulty,
+		Mode:       string(room.Mode),
+		Round:      round,
+		Rounds:     room.Rounds,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to notify players: %w", err)
+	}
+	if holder, ok := stateManager.CurrentTurn(); ok {
+		err = room.NotifyPlayers("TurnChanged", events.TurnChangedEventPayload{Username: string(holder)})
+		if err != nil {
+			return fmt.Errorf("failed to notify players: %w", err)
+		}
+	}
+	return nil
+}
+
+// nextEntry выбирает слово под сложность и тег комнаты, которое не повторяет слова комнаты и недавние слова игроков.
+// Если слов нужной сложности в категории нет, берётся любое слово с тегом комнаты.
+func (gs *GameServiceImpl) nextEntry(room *domain.Room, players []string) (repository.WordEntry, error) {
+	entry, err := gs.wordsRepo.NextEntry(room.ID, players, room.Category, repository.WordFilter{Difficulty: room.Difficulty, Tag: room.Tag})
+	if errors.Is(err, repository.ErrNoMatchingWords) {
+		entry, err = gs.wordsRepo.NextEntry(room.ID, players, room.Category, repository.WordFilter{Tag: room.Tag})
+	}
+	if errors.Is(err, repository.ErrNoMatchingWords) {
+		return entry, errs.NewError(tcp.StatusConflict, fmt.Sprintf("no words with tag %q in category %q", room.Tag, room.Category))
+	}
+	return entry, err
+}
+
+// CloseRoom освобождает слова, отложенные для удалённой комнаты
+func (gs *GameServiceImpl) CloseRoom(roomID string) {
+	gs.wordsRepo.ForgetRoom(roomID)
+}
+
+// attempts возвращает число попыток на слово: заданное в комнате или рассчитанное по сложности слова
+func (gs *GameServiceImpl) attempts(room *domain.Room, entry repository.WordEntry) int {
+	if room.Attempts > 0 {
+		return room.Attempts
+	}
+	return gs.wordsRepo.GetAttempts(entry, room.Difficulty)
+}
+
+// AdvanceMatch завершает раунд, когда игра окончена у всех игроков комнаты,
+// и запускает следующий раунд или подводит итоги матча
+func (gs *GameServiceImpl) AdvanceMatch(room *domain.Room) error {
+	round, stateManager := room.CurrentRound()
+	if round == 0 || stateManager == nil {
+		return nil
+	}
+
+	scores := make(map[domain.PlayerUsername]int)
+	for _, player := range room.GetAllPlayers() {
+		state, err := stateManager.GetState(domain.PlayerUsername(player))
+		if err != nil {
+			continue // Игрок без игры в этом раунде
+		}
+		if !state.IsGameOver {
+			return nil
+		}
+		scores[domain.PlayerUsername(player)] = state.Score
+	}
+
+	match, ok := room.CompleteRound(round, scores)
+	if !ok {
+		return nil // Раунд уже завершён параллельным ходом
+	}
+
+	err := room.NotifyPlayers("RoundFinished", events.RoundFinishedEventPayload{
+		Round:  round,
+		Rounds: match.Rounds,
+		Scores: usernameScores(scores),
+		Totals: usernameScores(match.Totals),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to notify players: %w", err)
+	}
+
+	if !match.IsFinished() {
+		if len(room.GetAllPlayers()) == 0 {
+			// Все игроки вышли — следующий раунд играть некому
+			room.SetState(domain.GameOver)
+			return nil
+		}
+		return gs.startRound(room, round+1)
+	}
+
+	standings := make([]events.MatchStandingPayload, 0, len(match.Totals))
+	for _, standing := range match.Standings() {
+		standings = append(standings, events.MatchStandingPayload{
+			Username: string(standing.Username),
+			Place:    standing.Place,
+			Total:    standing.Total,
+		})
+	}
+	roundScores := make([]map[string]int, 0, len(match.RoundScores))
+	for _, scores := range match.RoundScores {
+		roundScores = append(roundScores, usernameScores(scores))
+	}
+	err = room.NotifyPlayers("MatchFinished", events.MatchFinishedEventPayload{
+		Rounds:      match.Rounds,
+		Standings:   standings,
+		RoundScores: roundScores,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to notify players: %w", err)
@@ -48,16 +200,220 @@ func (gs *GameServiceImpl) StartGame(room *domain.Room) error {
 	return nil
 }
 
-// MakeGuess обрабатывает ход игрока
-func (gs *GameServiceImpl) MakeGuess(room *domain.Room, player *domain.Player, letter rune) (bool, string, error) {
+func usernameScores(scores map[domain.PlayerUsername]int) map[string]int {
+	result := make(map[string]int, len(scores))
+	for username, score := range scores {
+		result[string(username)] = score
+	}
+	return result
+}
+
+// MakeGuess обрабатывает ход игрока. Буква приводится к алфавиту категории, из которой роздано слово,
+// буква вне алфавита отклоняется без потери попытки.
+func (gs *GameServiceImpl) MakeGuess(room *domain.Room, player *domain.Player, letter rune) (domain.GuessOutcome, error) {
+	alphabet, err := gs.gameAlphabet(room)
+	if err != nil {
+		return domain.GuessOutcome{}, err
+	}
+	letter, err = alphabet.NormalizeLetter(letter)
+	if err != nil {
+		return domain.GuessOutcome{}, err
+	}
+	return gs.play(room, player, func(stateManager *domain.GameStateManager) (domain.GuessOutcome, error) {
+		return stateManager.MakeGuess(player, letter)
+	})
+}
+
+// GuessWord обрабатывает попытку отгадать слово целиком
+func (gs *GameServiceImpl) GuessWord(room *domain.Room, player *domain.Player, word string) (domain.GuessOutcome, error) {
+	alphabet, err := gs.gameAlphabet(room)
+	if err != nil {
+		return domain.GuessOutcome{}, err
+	}
+	word = alphabet.NormalizeWord(word)
+	return gs.play(room, player, func(stateManager *domain.GameStateManager) (domain.GuessOutcome, error) {
+		return stateManager.MakeWordGuess(player, word)
+	})
+}
+
+// gameAlphabet возвращает алфавит категории, из которой розданы слова текущего раунда.
+// Алфавиты удалённых при перезагрузке категорий сохраняются, поэтому раунд можно доиграть.
+func (gs *GameServiceImpl) gameAlphabet(room *domain.Room) (*domain.Alphabet, error) {
+	room.RLock()
+	category := room.Category
+	stateManager := room.StateManager
+	room.RUnlock()
+	if stateManager != nil && stateManager.Category() != "" {
+		category = stateManager.Category()
+	}
+	return gs.wordsRepo.GetAlphabet(category)
+}
+
+// RequestHint выдаёт подсказку в текущей игре игрока. Подсказка не передаёт ход.
+func (gs *GameServiceImpl) RequestHint(room *domain.Room, player *domain.Player) (domain.Hint, error) {
 	room.UpdateActivity()
 	room.RLock()
-	defer room.RUnlock()
-	if room.StateManager == nil {
-		return false, "", errors.New("no game in this room")
+	stateManager := room.StateManager
+	hintMode := room.HintMode
+	room.RUnlock()
+	if stateManager == nil {
+		return domain.Hint{}, errors.New("no game in this room")
+	}
+	hint, err := stateManager.RequestHint(player, hintMode)
+	if err != nil {
+		return domain.Hint{}, err
+	}
+	room.AwardPoints(domain.PlayerUsername(player.Username), -hint.Cost)
+	return hint, nil
+}
+
+// play выполняет ход в текущих играх комнаты, сообщает о нём соперникам и применяет правила режима
+func (gs *GameServiceImpl) play(room *domain.Room, player *domain.Player, move func(*domain.GameStateManager) (domain.GuessOutcome, error)) (domain.GuessOutcome, error) {
+	room.UpdateActivity()
+	room.RLock()
+	stateManager := room.StateManager
+	room.RUnlock()
+	if stateManager == nil {
+		return domain.GuessOutcome{}, errors.New("no game in this room")
+	}
+
+	outcome, err := move(stateManager)
+	if err != nil {
+		return domain.GuessOutcome{}, err
+	}
+	if !outcome.Counts() {
+		return outcome, nil // Повтор буквы не засчитывается как ход
+	}
+	room.AwardPoints(domain.PlayerUsername(player.Username), outcome.Points)
+	payload := guessMadePayload(room.Mode, player.Username, outcome)
+	if err := room.NotifyOthers("GuessMade", payload, player.Username); err != nil {
+		return domain.GuessOutcome{}, err
+	}
+	// Зрители видят ход так же, как состояние игры, — без букв
+	payload.Letter, payload.Word = "", ""
+	payload.WordProgress = domain.MaskProgress(outcome.WordProgress)
+	if err := room.NotifySpectators("GuessMade", payload); err != nil {
+		return domain.GuessOutcome{}, err
+	}
+	switch room.Mode {
+	case domain.ModeTurnBased:
+		// Ход переходит к следующему игроку после каждой попытки
+		if err := room.NextTurn(); err != nil {
+			return domain.GuessOutcome{}, err
+		}
+	case domain.ModeRace:
+		if err := gs.finishRace(room, stateManager, player.Username); err != nil {
+			return domain.GuessOutcome{}, err
+		}
 	}
+	return outcome, nil
+}
+
+// TimeoutTurn штрафует игрока, у которого истекло время хода, и в режиме очереди передаёт ход дальше.
+// false — игрок успел сходить, пока истекало время.
+func (gs *GameServiceImpl) TimeoutTurn(room *domain.Room, username domain.PlayerUsername, started time.Time) (domain.GuessOutcome, bool, error) {
+	room.RLock()
+	stateManager := room.StateManager
+	room.RUnlock()
+	if stateManager == nil {
+		return domain.GuessOutcome{}, false, nil
+	}
+
+	outcome, ok := stateManager.MissTurn(username, started)
+	if !ok {
+		return domain.GuessOutcome{}, false, nil
+	}
+	room.AwardPoints(username, outcome.Points)
+	payload := turnTimedOutPayload(username, "turn", outcome)
+	if room.Mode == domain.ModeRace {
+		payload.Answer = "" // Соперники ещё отгадывают то же слово
+	}
+	if err := room.NotifyPlayers("TurnTimedOut", payload); err != nil {
+		return domain.GuessOutcome{}, false, err
+	}
+	if room.Mode == domain.ModeTurnBased {
+		if err := room.NextTurn(); err != nil {
+			return domain.GuessOutcome{}, false, err
+		}
+	}
+	return outcome, true, nil
+}
+
+// TimeoutGame засчитывает поражение всем, кто не закончил игру раунда до конца времени игры,
+// и возвращает этих игроков.
+func (gs *GameServiceImpl) TimeoutGame(room *domain.Room) ([]domain.PlayerUsername, error) {
+	room.RLock()
+	stateManager := room.StateManager
+	room.RUnlock()
+	if stateManager == nil {
+		return nil, nil
+	}
+
+	outcomes := stateManager.Forfeit()
+	players := make([]domain.PlayerUsername, 0, len(outcomes))
+	for username, outcome := range outcomes {
+		players = append(players, username)
+		if err := room.NotifyPlayers("TurnTimedOut", turnTimedOutPayload(username, "game", outcome)); err != nil {
+			return players, err
+		}
+	}
+	return players, nil
+}
+
+func turnTimedOutPayload(username domain.PlayerUsername, reason string, outcome domain.GuessOutcome) events.TurnTimedOutEventPayload {
+	return events.TurnTimedOutEventPayload{
+		Username:     string(username),
+		Reason:       reason,
+		Result:       string(outcome.Result),
+		Points:       outcome.Points,
+		AttemptsLeft: outcome.AttemptsLeft,
+		Answer:       outcome.Answer,
+	}
+}
 
-	return room.StateManager.MakeGuess(player, letter)
+// guessMadePayload описывает ход для соперников. В гонке слово общее,
+// поэтому буквы и позиции соперникам не раскрываются.
+func guessMadePayload(mode domain.GameMode, username string, outcome domain.GuessOutcome) events.GuessMadeEventPayload {
+	payload := events.GuessMadeEventPayload{
+		Username:     username,
+		Result:       string(outcome.Result),
+		Points:       outcome.Points,
+		AttemptsLeft: outcome.AttemptsLeft,
+	}
+	if mode == domain.ModeRace {
+		return payload
+	}
+	if outcome.Letter != 0 {
+		payload.Letter = string(outcome.Letter)
+	}
+	payload.Word = outcome.Word
+	payload.Positions = outcome.Positions
+	payload.WordProgress = outcome.WordProgress
+	return payload
+}
+
+// finishRace завершает гонку, если игрок первым отгадал слово, и объявляет места.
+func (gs *GameServiceImpl) finishRace(room *domain.Room, stateManager *domain.GameStateManager, username string) error {
+	result, ok := stateManager.FinishRace(domain.PlayerUsername(username))
+	if !ok {
+		return nil
+	}
+
+	standings := make([]events.RacePlacementPayload, 0, len(result.Standings))
+	for _, placement := range result.Standings {
+		room.AwardPoints(placement.Username, placement.Bonus)
+		standings = append(standings, events.RacePlacementPayload{
+			Username: string(placement.Username),
+			Place:    placement.Place,
+			Bonus:    placement.Bonus,
+			Score:    placement.Score,
+		})
+	}
+	return room.NotifyPlayers("RoundWon", events.RoundWonEventPayload{
+		Winner:    string(result.Winner),
+		Word:      result.Word,
+		Standings: standings,
+	})
 }
 
 // GetGameState возвращает текущее состояние игры для всех игроков в комнате
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"hangman/internal/domain"
+	"hangman/internal/errs"
+	"hangman/internal/repository"
+	tcp "hangman/pkg/tcp-server"
+	"hangman/pkg/utils"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testContext возвращает контекст соединения с сервером уведомлений без подписчиков
+func testContext() context.Context {
+	logger := utils.NewCustomLogger(utils.LevelError)
+	ctx := tcp.SetLogger(context.Background(), logger)
+	return tcp.SetNotificationServer(ctx, tcp.NewNotificationServer("", tcp.NewSessionManager(), logger))
+}
+
+func newTestRoom(t *testing.T, id, owner string, settings domain.RoomSettings) *domain.Room {
+	t.Helper()
+	return domain.NewRoom(testContext(), id, &owner, "", settings)
+}
+
+func newTestGameService(t *testing.T) domain.IGameService {
+	t.Helper()
+	words, err := repository.NewWordsRepository("../../../assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewGameService(words)
+}
+
+func TestStartGameWithoutPlayers(t *testing.T) {
+	gs := newTestGameService(t)
+	settings := domain.DefaultRoomSettings()
+	settings.Mode = domain.ModeTurnBased
+	room := newTestRoom(t, "empty", "alice", settings)
+
+	// Владелец создал комнату, но не сел за стол
+	var appErr *errs.Error
+	if err := gs.StartGame(room); !errors.As(err, &appErr) || appErr.Code != tcp.StatusConflict {
+		t.Fatalf("expected conflict, got %v", err)
+	}
+	if room.RoomState != domain.Waiting {
+		t.Errorf("room must stay waiting, got %v", room.RoomState)
+	}
+	if err := room.SyncTurn(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAdvanceMatchAfterEveryoneLeft(t *testing.T) {
+	gs := newTestGameService(t)
+	settings := domain.DefaultRoomSettings()
+	settings.Category, settings.Mode, settings.Rounds = "животные", domain.ModeTurnBased, 2
+	room := newTestRoom(t, "left", "alice", settings)
+	room.AddPlayer(domain.NewPlayer(nil, "alice", 0))
+	room.AddPlayer(domain.NewPlayer(nil, "bob", 0))
+	if err := gs.StartGame(room); err != nil {
+		t.Fatal(err)
+	}
+
+	room.KickPlayer("alice")
+	room.KickPlayer("bob")
+	if err := gs.AdvanceMatch(room); err != nil {
+		t.Fatal(err)
+	}
+	if room.RoomState != domain.GameOver {
+		t.Errorf("match without players must end, got %v", room.RoomState)
+	}
+	if err := room.SyncTurn(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStartGameWithTag(t *testing.T) {
+	dir := t.TempDir()
+	pack := `{"столицы": {"words": [
+		"осло",
+		{"word": "париж", "difficulty": 9, "tags": ["Европа"]},
+		{"word": "токио", "difficulty": 2, "tags": ["азия"]}
+	]}}`
+	if err := os.WriteFile(filepath.Join(dir, "words.json"), []byte(pack), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	words, err := repository.NewWordsRepository(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := NewGameService(words)
+
+	settings := domain.DefaultRoomSettings()
+	settings.Category, settings.Mode, settings.Tag = "столицы", domain.ModeTurnBased, "европа"
+	room := newTestRoom(t, "tagged", "alice", settings)
+	room.AddPlayer(domain.NewPlayer(nil, "alice", 0))
+
+	// Под medium слов с тегом нет, поэтому сложность не учитывается, а тег — да
+	for i := 0; i < 5; i++ {
+		if err := gs.StartGame(room); err != nil {
+			t.Fatal(err)
+		}
+		if game := room.StateManager.Snapshot()["alice"]; game.Word != "париж" {
+			t.Fatalf("expected a word tagged европа, got %q", game.Word)
+		}
+	}
+
+	room.Tag = "африка"
+	var appErr *errs.Error
+	if err := gs.StartGame(room); !errors.As(err, &appErr) || appErr.Code != tcp.StatusConflict {
+		t.Fatalf("expected conflict for a tag without words, got %v", err)
+	}
+}
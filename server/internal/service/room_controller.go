@@ -3,11 +3,13 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"hangman/internal/domain"
 	"hangman/internal/errs"
 	"hangman/internal/events"
 	ctx_repo "hangman/pkg/ctx-repo"
 	tcp "hangman/pkg/tcp-server"
+	"hangman/pkg/utils"
 	"time"
 )
 
@@ -16,6 +18,7 @@ type RoomController struct {
 	playerRepo  domain.IPlayerRepository
 	gameService domain.IGameService
 	ctxRepo     *ctx_repo.CtxRepository
+	timers      *TimerScheduler
 }
 
 func NewRoomController(
@@ -29,23 +32,21 @@ func NewRoomController(
 		playerRepo:  playerRepo,
 		gameService: gameService,
 		ctxRepo:     ctxRepo,
+		timers:      NewTimerScheduler(),
 	}
 }
 
 func (rc *RoomController) CheckUsernameUniqueness(username string) bool {
-	return !rc.playerRepo.PlayerExists(username)
+	_, err := rc.playerRepo.GetAccount(username)
+	return err != nil
 }
 
-func (rc *RoomController) CreateRoom(ctx context.Context, player string, roomID, password, category, difficulty string) (*domain.Room, error) {
-	room := domain.NewRoom(
-		ctx,
-		roomID,
-		&player,
-		3,
-		password,
-		category,
-		difficulty,
-	)
+func (rc *RoomController) CreateRoom(ctx context.Context, player string, roomID, password string, options domain.RoomOptions) (*domain.Room, error) {
+	settings, err := domain.DefaultRoomSettings().Apply(options)
+	if err != nil {
+		return nil, errs.NewError(tcp.StatusBadRequest, err.Error())
+	}
+	room := domain.NewRoom(ctx, roomID, &player, password, settings)
 	if err := rc.roomRepo.AddRoom(room); err != nil {
 		return nil, err
 	}
@@ -58,9 +59,9 @@ func (rc *RoomController) CreateRoom(ctx context.Context, player string, roomID,
 	return room, nil
 }
 
-func (rc *RoomController) UpdateRoom(roomID string, clientKey domain.ClientKey, newPassword, newCategory, newDifficulty *string) (*domain.Room, error) {
-	// Получаем данные игрока по ключу клиента
-	player, err := rc.playerRepo.GetPlayerByKey(clientKey)
+func (rc *RoomController) UpdateRoom(roomID string, username string, newPassword *string, options domain.RoomOptions) (*domain.Room, error) {
+	// Получаем данные игрока
+	player, err := rc.playerRepo.GetPlayerByUsername(username)
 	if err != nil {
 		return nil, errs.NewError(tcp.StatusNotFound, "player not found")
 	}
@@ -75,16 +76,28 @@ func (rc *RoomController) UpdateRoom(roomID string, clientKey domain.ClientKey,
 	if room.Owner == nil || *room.Owner != player.Username {
 		return nil, errs.NewError(tcp.StatusUnauthorized, "only the owner can update the room")
 	}
+	// Число раундов, попытки и время хода применяются со следующего раунда или матча
+	settings, err := room.RoomSettings.Apply(options)
+	if err != nil {
+		return nil, errs.NewError(tcp.StatusBadRequest, err.Error())
+	}
+	// Режим и категорию нельзя сменить посреди игры
+	if room.RoomState == domain.InProgress && settings.Mode != room.Mode {
+		return nil, errs.NewError(tcp.StatusConflict, "cannot change mode while the game is in progress")
+	}
+	if room.RoomState == domain.InProgress && settings.Category != room.Category {
+		return nil, errs.NewError(tcp.StatusConflict, "cannot change category while the game is in progress")
+	}
+	if settings.MaxPlayers < room.GetPlayerCount() {
+		return nil, errs.NewError(tcp.StatusConflict, "max_players cannot be less than the current number of players")
+	}
 	// Обновить поля комнаты, если предоставлены новые значения
+	room.Lock()
+	room.RoomSettings = settings
+	room.Unlock()
 	if newPassword != nil {
 		room.Password = *newPassword
 	}
-	if newCategory != nil {
-		room.Category = *newCategory
-	}
-	if newDifficulty != nil {
-		room.Difficulty = *newDifficulty
-	}
 
 	// Сохранить обновленную комнату в репозитории
 	if err := rc.roomRepo.UpdateRoom(room); err != nil {
@@ -99,7 +112,7 @@ func (rc *RoomController) UpdateRoom(roomID string, clientKey domain.ClientKey,
 	return room, nil
 }
 
-func (rc *RoomController) JoinRoom(ctx context.Context, username, roomID, password string) (*domain.Room, error) {
+func (rc *RoomController) JoinRoom(ctx context.Context, username, roomID, password string, spectator bool) (*domain.Room, error) {
 	room, err := rc.roomRepo.GetRoomByID(roomID)
 	if err != nil {
 		return nil, err
@@ -109,14 +122,23 @@ func (rc *RoomController) JoinRoom(ctx context.Context, username, roomID, passwo
 	if room.Password != "" && room.Password != password {
 		return nil, errs.NewError(tcp.StatusUnauthorized, "incorrect password")
 	}
-	clientKey := domain.NewClientKey(username, password)
+
+	// Игрок должен быть авторизован
+	player, err := rc.playerRepo.GetPlayerByUsername(username)
+	if err != nil {
+		return nil, errs.NewError(tcp.StatusUnauthorized, "player is not logged in")
+	}
+
+	if spectator {
+		return rc.joinAsSpectator(ctx, room, player)
+	}
+	if room.HasSpectator(username) {
+		return nil, errs.NewError(tcp.StatusConflict, "leave the room as a spectator before joining as a player")
+	}
 
 	// Проверяем текущего игрока
 	existingPlayer := room.HasPlayer(username)
 
-	conn, _ := tcp.GetConn(ctx)
-	connAddr := conn.RemoteAddr().String()
-
 	switch room.RoomState {
 	case domain.Waiting, domain.GameOver:
 		if !existingPlayer && room.GetPlayerCount() >= room.MaxPlayers {
@@ -124,22 +146,15 @@ func (rc *RoomController) JoinRoom(ctx context.Context, username, roomID, passwo
 			return nil, errs.NewError(tcp.StatusConflict, "room is full, cannot join")
 		}
 
-		// Новый или реконнект существующего игрока
-		player := domain.NewPlayer(&conn, username, 0)
-
-		// Добавляем/обновляем игрока в репозитории
-		err = rc.playerRepo.AddPlayer(clientKey, player)
-		if err != nil {
-			return nil, err
-		}
-
-		//Обновляем контекст пользователя
-		rc.ctxRepo.UpdateOrInsertCtx(connAddr, ctx)
-		newCtx, _ := rc.ctxRepo.GetContext(connAddr)
-		// Если игрока еще нет в комнате, добавляем
-		if !existingPlayer {
+		switch {
+		case !existingPlayer:
+			// Если игрока еще нет в комнате, добавляем
 			room.AddPlayer(player)
-			room.MonitorContext(*newCtx, username)
+			room.MonitorContext(rc.membershipCtx(ctx, username, roomID), username)
+		case !room.IsPlayerActive(domain.PlayerUsername(username)):
+			// Игрок комнаты, восстановленной из снимка, возвращается с новым подключением
+			room.ReconnectPlayer(player)
+			room.MonitorContext(rc.membershipCtx(ctx, username, roomID), username)
 		}
 
 		err = room.NotifyPlayers("PlayerJoined", events.PlayerJoinedEventPayload{Username: player.Username})
@@ -154,64 +169,85 @@ func (rc *RoomController) JoinRoom(ctx context.Context, username, roomID, passwo
 			return nil, errs.NewError(tcp.StatusConflict, "game already in progress, new players cannot join")
 		}
 
-		// Получаем информацию о существующем игроке
-		existingPlayerData, err := rc.playerRepo.GetPlayerByKey(clientKey)
-		if err != nil {
-			return nil, err
-		}
-
-		// Обновляем информацию о клиенте в репозитории
-		err = rc.playerRepo.AddPlayer(clientKey, existingPlayerData)
-		if err != nil {
-			return nil, err
-		}
-
 		//Обновляем контекст пользователя
-		rc.ctxRepo.UpdateOrInsertCtx(connAddr, ctx)
+		room.ReconnectPlayer(player)
+		room.MonitorContext(rc.membershipCtx(ctx, username, roomID), username)
 
 		err = room.NotifyPlayers("PlayerJoined", events.PlayerJoinedEventPayload{Username: username})
 		if err != nil {
 			return nil, err
 		}
+		// Если ход застрял у отключённого игрока, передаём его дальше
+		if err := room.SyncTurn(); err != nil {
+			return nil, err
+		}
+		// После перезапуска сервера таймеры комнаты запускаются с возвращением первого игрока
+		rc.startTimers(room)
 		return room, nil
 	}
 
 	return nil, errs.NewError(tcp.StatusInternalServerError, "unknown room state")
 }
 
-func (rc *RoomController) LeaveRoom(clientKey domain.ClientKey, roomID string) error {
+// joinAsSpectator добавляет зрителя. Смотреть можно в любом состоянии комнаты, место игрока не занимается.
+func (rc *RoomController) joinAsSpectator(ctx context.Context, room *domain.Room, player *domain.Player) (*domain.Room, error) {
+	if room.HasPlayer(player.Username) {
+		return nil, errs.NewError(tcp.StatusConflict, "players cannot join their room as spectators")
+	}
+	if !room.HasSpectator(player.Username) {
+		room.AddSpectator(player)
+		room.MonitorContext(rc.membershipCtx(ctx, player.Username, room.ID), player.Username)
+	}
+	err := room.NotifyPlayers("SpectatorJoined", events.SpectatorJoinedEventPayload{Username: player.Username})
+	if err != nil {
+		return nil, err
+	}
+	return room, nil
+}
+
+// membershipCtx создаёт контекст участия игрока в комнате.
+// Он отменяется при выходе из комнаты или при закрытии соединения.
+func (rc *RoomController) membershipCtx(ctx context.Context, username, roomID string) context.Context {
+	key := membershipKey(username, roomID)
+	rc.ctxRepo.UpdateOrInsertCtx(key, ctx)
+	newCtx, _ := rc.ctxRepo.GetContext(key)
+	return *newCtx
+}
+
+func membershipKey(username, roomID string) string {
+	return username + "@" + roomID
+}
+
+func (rc *RoomController) LeaveRoom(username string, roomID string) error {
 	room, err := rc.roomRepo.GetRoomByID(roomID)
 	if err != nil {
 		return err
 	}
-	player, err := rc.playerRepo.GetPlayerByKey(clientKey)
+	player, err := rc.playerRepo.GetPlayerByUsername(username)
 	if err != nil {
 		return err
 	}
-	connAddr := (*player.Conn).RemoteAddr().String()
-	rc.ctxRepo.CancelContext(connAddr)
+	rc.ctxRepo.CancelContext(membershipKey(player.Username, roomID))
+	if room.HasSpectator(player.Username) {
+		room.KickPlayer(player.Username)
+		return room.NotifyPlayers("SpectatorLeft", events.SpectatorLeftEventPayload{Username: player.Username})
+	}
 	room.KickPlayer(player.Username) // Удаление из комнаты
 	err = room.NotifyPlayers("PlayerLeft", events.PlayerLeftEventPayload{Username: player.Username})
 	if err != nil {
 		return err
 	}
-	err = rc.playerRepo.RemovePlayerByUsername(player.Username) // Удаление из глобального репозитория
-	if err != nil {
-		return err
-	}
+	// Раунд мог ждать только ушедшего игрока
+	rc.advanceMatch(room)
 	return nil
 }
 
-func (rc *RoomController) DeleteRoom(clientKey domain.ClientKey, roomID string) error {
+func (rc *RoomController) DeleteRoom(username string, roomID string) error {
 	room, err := rc.roomRepo.GetRoomByID(roomID)
 	if err != nil {
 		return err // Комната не найдена
 	}
-	player, err := rc.playerRepo.GetPlayerByKey(clientKey)
-	if err != nil {
-		return err
-	}
-	err = rc.playerRepo.UpdatePlayerActivity(clientKey)
+	player, err := rc.playerRepo.GetPlayerByUsername(username)
 	if err != nil {
 		return err
 	}
@@ -225,11 +261,9 @@ func (rc *RoomController) DeleteRoom(clientKey domain.ClientKey, roomID string)
 	}
 	// Удаляем всех игроков из комнаты
 	for _, player := range room.GetAllPlayers() {
-		err := rc.playerRepo.RemovePlayerByUsername(player)
-		if err != nil {
-			return err
-		}
+		rc.ctxRepo.CancelContext(membershipKey(player, roomID))
 	}
+	rc.closeRoom(roomID)
 
 	// Удаляем комнату из репозитория
 	if err := rc.roomRepo.RemoveRoom(roomID); err != nil {
@@ -239,6 +273,12 @@ func (rc *RoomController) DeleteRoom(clientKey domain.ClientKey, roomID string)
 	return nil
 }
 
+// closeRoom останавливает таймеры и освобождает слова удаляемой комнаты
+func (rc *RoomController) closeRoom(roomID string) {
+	rc.timers.Stop(roomID)
+	rc.gameService.CloseRoom(roomID)
+}
+
 func (rc *RoomController) forceDeleteRoom(roomID string) error {
 	room, err := rc.roomRepo.GetRoomByID(roomID)
 	if err != nil {
@@ -250,11 +290,9 @@ func (rc *RoomController) forceDeleteRoom(roomID string) error {
 	room.RUnlock()
 
 	for _, player := range players {
-		err := rc.playerRepo.RemovePlayerByUsername(player)
-		if err != nil {
-			return err
-		}
+		rc.ctxRepo.CancelContext(membershipKey(player, roomID))
 	}
+	rc.closeRoom(roomID)
 
 	if err := rc.roomRepo.RemoveRoom(roomID); err != nil {
 		return err
@@ -277,7 +315,7 @@ func (rc *RoomController) CleanupRooms(timeoutSeconds int) {
 	}
 }
 
-func (rc *RoomController) StartGame(clientKey domain.ClientKey, roomID string) error {
+func (rc *RoomController) StartGame(username string, roomID string) error {
 	room, err := rc.roomRepo.GetRoomByID(roomID)
 	if err != nil {
 		return err
@@ -287,11 +325,7 @@ func (rc *RoomController) StartGame(clientKey domain.ClientKey, roomID string) e
 	//if room.RoomState == domain.InProgress {
 	//	return errors.New("game already started")
 	//}
-	player, err := rc.playerRepo.GetPlayerByKey(clientKey)
-	if err != nil {
-		return err
-	}
-	err = rc.playerRepo.UpdatePlayerActivity(clientKey)
+	player, err := rc.playerRepo.GetPlayerByUsername(username)
 	if err != nil {
 		return err
 	}
@@ -299,32 +333,173 @@ func (rc *RoomController) StartGame(clientKey domain.ClientKey, roomID string) e
 		return errs.NewError(tcp.StatusUnauthorized, "only the owner can start the game")
 	}
 
-	return rc.gameService.StartGame(room)
+	if err := rc.gameService.StartGame(room); err != nil {
+		return err
+	}
+	rc.startTimers(room)
+	return nil
 }
 
-func (rc *RoomController) MakeGuess(clientKey domain.ClientKey, roomID string, letter rune) (bool, string, error) {
-	player, err := rc.playerRepo.GetPlayerByKey(clientKey)
+// MakeGuess проверяет букву и возвращает исход хода и состояние игры игрока после него
+func (rc *RoomController) MakeGuess(username string, roomID string, letter rune) (domain.GuessOutcome, domain.GameState, error) {
+	return rc.play(username, roomID, func(room *domain.Room, player *domain.Player) (domain.GuessOutcome, error) {
+		return rc.gameService.MakeGuess(room, player, letter)
+	})
+}
+
+// GuessWord проверяет слово целиком и возвращает исход хода и состояние игры игрока после него
+func (rc *RoomController) GuessWord(username string, roomID string, word string) (domain.GuessOutcome, domain.GameState, error) {
+	return rc.play(username, roomID, func(room *domain.Room, player *domain.Player) (domain.GuessOutcome, error) {
+		return rc.gameService.GuessWord(room, player, word)
+	})
+}
+
+// RequestHint выдаёт игроку подсказку за очки
+func (rc *RoomController) RequestHint(username string, roomID string) (domain.Hint, error) {
+	player, err := rc.playerRepo.GetPlayerByUsername(username)
 	if err != nil {
-		return false, "", err
+		return domain.Hint{}, err
 	}
 	room, err := rc.roomRepo.GetRoomByID(roomID)
 	if err != nil {
-		return false, "", err
+		return domain.Hint{}, err
+	}
+	if room.HasSpectator(username) {
+		return domain.Hint{}, errs.NewError(tcp.StatusUnauthorized, "spectators cannot play")
+	}
+	hint, err := rc.gameService.RequestHint(room, player)
+	switch {
+	case errors.Is(err, domain.ErrNotYourTurn):
+		return domain.Hint{}, errs.NewError(tcp.StatusUnauthorized, "it is not your turn")
+	case errors.Is(err, domain.ErrNoHintsLeft):
+		return domain.Hint{}, errs.NewError(tcp.StatusConflict, "no hints left in this game")
+	case errors.Is(err, domain.ErrHintUnavailable):
+		return domain.Hint{}, errs.NewError(tcp.StatusConflict, "cannot reveal the last letter")
+	case err != nil:
+		return domain.Hint{}, err
+	}
+	return hint, nil
+}
+
+// play выполняет ход игрока, записывает результат завершённой игры и продвигает матч
+func (rc *RoomController) play(username, roomID string, move func(*domain.Room, *domain.Player) (domain.GuessOutcome, error)) (domain.GuessOutcome, domain.GameState, error) {
+	player, err := rc.playerRepo.GetPlayerByUsername(username)
+	if err != nil {
+		return domain.GuessOutcome{}, domain.GameState{}, err
 	}
-	err = rc.playerRepo.UpdatePlayerActivity(clientKey)
+	room, err := rc.roomRepo.GetRoomByID(roomID)
 	if err != nil {
-		return false, "", err
+		return domain.GuessOutcome{}, domain.GameState{}, err
+	}
+	if room.HasSpectator(username) {
+		return domain.GuessOutcome{}, domain.GameState{}, errs.NewError(tcp.StatusUnauthorized, "spectators cannot play")
+	}
+	// Игры раунда берутся под блокировкой комнаты: ход соперника может начать следующий раунд
+	_, stateManager := room.CurrentRound()
+	if stateManager == nil {
+		return domain.GuessOutcome{}, domain.GameState{}, errs.NewError(tcp.StatusConflict, "no game in this room")
+	}
+	outcome, err := move(room, player)
+	if errors.Is(err, domain.ErrNotYourTurn) {
+		return domain.GuessOutcome{}, domain.GameState{}, errs.NewError(tcp.StatusUnauthorized, "it is not your turn")
+	}
+	if errors.Is(err, domain.ErrLetterNotInAlphabet) {
+		return domain.GuessOutcome{}, domain.GameState{}, errs.NewError(tcp.StatusBadRequest, "letter is not in the alphabet of this category")
+	}
+	if err != nil {
+		return domain.GuessOutcome{}, domain.GameState{}, err
+	}
+	// Состояние снимается до перехода к следующему раунду
+	state, err := stateManager.GetState(domain.PlayerUsername(username))
+	if err != nil {
+		return domain.GuessOutcome{}, domain.GameState{}, err
+	}
+	// Ход уже засчитан, поэтому ошибки статистики и следующего раунда не делают его неудачным
+	if err := rc.recordIfGameOver(room, stateManager, username); err != nil {
+		utils.NewCustomLogger(utils.LevelInfo).Error(fmt.Sprintf("Room %s: failed to record results: %v", room.ID, err))
+	}
+	rc.advanceMatch(room)
+	return outcome, state, nil
+}
+
+// advanceMatch переходит к следующему раунду, если текущий окончен у всех.
+// Если следующий раунд начать не удалось (например, в категории не осталось слов),
+// матч прерывается: комната переходит в GameOver, а игроки получают MatchAborted.
+func (rc *RoomController) advanceMatch(room *domain.Room) {
+	err := rc.gameService.AdvanceMatch(room)
+	if err == nil {
+		return
+	}
+	logger := utils.NewCustomLogger(utils.LevelInfo)
+	logger.Error(fmt.Sprintf("Room %s: failed to start the next round: %v", room.ID, err))
+
+	round, _ := room.CurrentRound()
+	room.SetState(domain.GameOver)
+	reason := "failed to start the next round"
+	var appErr *errs.Error
+	if errors.As(err, &appErr) {
+		reason = appErr.Message
 	}
-	return rc.gameService.MakeGuess(room, player, letter)
+	if err := room.NotifyPlayers("MatchAborted", events.MatchAbortedEventPayload{Round: round, Reason: reason}); err != nil {
+		logger.Error(fmt.Sprintf("Room %s: %v", room.ID, err))
+	}
+}
+
+// recordIfGameOver сохраняет результат игрока в статистику, если его игра завершилась.
+// Общее слово завершается сразу для всех участников, а победа в гонке закрывает игры соперников.
+func (rc *RoomController) recordIfGameOver(room *domain.Room, stateManager *domain.GameStateManager, username string) error {
+	players := []domain.PlayerUsername{domain.PlayerUsername(username)}
+	switch room.Mode {
+	case domain.ModeTurnBased:
+		players = stateManager.Players()
+	case domain.ModeRace:
+		if winner, ok := stateManager.RaceWinner(); ok && string(winner) == username {
+			players = append(players, rc.closedByRace(stateManager, winner)...)
+		}
+	}
+	return rc.recordResults(stateManager, players)
 }
 
-func (rc *RoomController) GetRoomState(roomID, password string) (*domain.Room, error) {
+// recordResults сохраняет в статистику результаты игроков, если их игры завершились
+func (rc *RoomController) recordResults(stateManager *domain.GameStateManager, players []domain.PlayerUsername) error {
+	for _, player := range players {
+		state, err := stateManager.GetState(player)
+		if err != nil {
+			return err
+		}
+		if !state.IsGameOver {
+			return nil
+		}
+		if err := rc.playerRepo.RecordGameResult(string(player), state.Score, state.IsWon); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// closedByRace возвращает соперников, чьи игры закрыла победа в гонке:
+// они не отгадали слово, но попытки у них ещё оставались.
+func (rc *RoomController) closedByRace(stateManager *domain.GameStateManager, winner domain.PlayerUsername) []domain.PlayerUsername {
+	var closed []domain.PlayerUsername
+	for _, player := range stateManager.Players() {
+		if player == winner {
+			continue
+		}
+		state, err := stateManager.GetState(player)
+		if err == nil && state.IsGameOver && !state.IsWon && state.AttemptsLeft > 0 {
+			closed = append(closed, player)
+		}
+	}
+	return closed
+}
+
+func (rc *RoomController) GetRoomState(username, roomID, password string) (*domain.Room, error) {
 	room, err := rc.roomRepo.GetRoomByID(roomID)
 	if err != nil {
 		return nil, err
 	}
-	// Проверяем пароль
-	if room.Password != "" && room.Password != password {
+	// Участникам и зрителям комнаты пароль не нужен, остальные должны его знать
+	if !room.HasPlayer(username) && !room.HasSpectator(username) && room.Password != "" && room.Password != password {
 		return nil, errs.NewError(tcp.StatusUnauthorized, "incorrect password")
 	}
 	err = rc.HandleOwnerChange(room)
@@ -335,18 +510,32 @@ func (rc *RoomController) GetRoomState(roomID, password string) (*domain.Room, e
 	return room, nil
 }
 
-func (rc *RoomController) GetGameState(roomID string) (map[string]*domain.GameState, error) {
+// GetGameState возвращает состояния игр всех игроков комнаты. Зрители видят их в замаскированном виде.
+func (rc *RoomController) GetGameState(username, roomID string) (map[string]*domain.GameState, error) {
 	room, err := rc.roomRepo.GetRoomByID(roomID)
 	if err != nil {
 		return nil, err
 	}
+	// Состояние видят только участники комнаты, открытые буквы — только игроки
+	isPlayer := room.HasPlayer(username)
+	if !isPlayer && !room.HasSpectator(username) {
+		return nil, errs.NewError(tcp.StatusUnauthorized, "join the room to see the game state")
+	}
 	// Проверяем, закончилась ли игра у всех игроков
 	err = rc.CheckAndSetGameOver(room)
 	if err != nil {
 		return nil, err
 	}
 
-	return rc.gameService.GetGameState(room)
+	states, err := rc.gameService.GetGameState(room)
+	if err != nil || isPlayer {
+		return states, err
+	}
+	for player, state := range states {
+		masked := state.Masked()
+		states[player] = &masked
+	}
+	return states, nil
 }
 
 func (rc *RoomController) HandleOwnerChange(room *domain.Room) error {
@@ -401,11 +590,18 @@ func (rc *RoomController) CheckAndSetGameOver(room *domain.Room) error {
 
 	return nil
 }
+
+// GetAllRooms возвращает открытые комнаты. Приватные комнаты доступны только по идентификатору.
 func (rc *RoomController) GetAllRooms() ([]*domain.Room, error) {
-	return rc.roomRepo.GetAllRooms(), nil
+	var rooms []*domain.Room
+	for _, room := range rc.roomRepo.GetAllRooms() {
+		if !room.Private {
+			rooms = append(rooms, room)
+		}
+	}
+	return rooms, nil
 }
 
-func (rc *RoomController) GetLeaderboard() (map[string]int, error) {
-	leaderboard := rc.playerRepo.GetPlayerUsernamesAndScores()
-	return leaderboard, nil
+func (rc *RoomController) GetLeaderboard() ([]domain.PlayerStats, error) {
+	return rc.playerRepo.GetLeaderboard()
 }
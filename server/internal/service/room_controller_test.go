@@ -0,0 +1,157 @@
+package service
+
+import (
+	"errors"
+	"hangman/internal/domain"
+	"hangman/internal/errs"
+	"hangman/internal/repository"
+	ctx_repo "hangman/pkg/ctx-repo"
+	tcp "hangman/pkg/tcp-server"
+	"testing"
+)
+
+// newTestController создаёт контроллер с авторизованными игроками players
+func newTestController(t *testing.T, players ...string) *RoomController {
+	t.Helper()
+	playerRepo := repository.NewPlayerRepository()
+	for _, username := range players {
+		if err := playerRepo.AddPlayer(domain.NewPlayer(nil, username, 0)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewRoomController(repository.NewRoomRepository(), playerRepo, newTestGameService(t), ctx_repo.NewCtxRepository())
+}
+
+// startTestRoom создаёт комнату первого из players, сажает в неё игроков и начинает игру
+func startTestRoom(t *testing.T, rc *RoomController, roomID string, players ...string) *domain.Room {
+	t.Helper()
+	return startTestRoomWith(t, rc, roomID, domain.RoomOptions{}, players...)
+}
+
+func startTestRoomWith(t *testing.T, rc *RoomController, roomID string, options domain.RoomOptions, players ...string) *domain.Room {
+	t.Helper()
+	if options.Category == nil {
+		category := "животные"
+		options.Category = &category
+	}
+	room, err := rc.CreateRoom(testContext(), players[0], roomID, "", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, username := range players {
+		if _, err := rc.JoinRoom(testContext(), username, roomID, "", false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rc.StartGame(players[0], roomID); err != nil {
+		t.Fatal(err)
+	}
+	return room
+}
+
+func expectCode(t *testing.T, err error, code int32) {
+	t.Helper()
+	var appErr *errs.Error
+	if !errors.As(err, &appErr) || appErr.Code != code {
+		t.Fatalf("expected code %d, got %v", code, err)
+	}
+}
+
+func TestCategoryIsFixedDuringGame(t *testing.T) {
+	rc := newTestController(t, "alice")
+	room := startTestRoom(t, rc, "fixed", "alice")
+	defer rc.closeRoom(room.ID)
+
+	fruits := "фрукты"
+	_, err := rc.UpdateRoom(room.ID, "alice", nil, domain.RoomOptions{Category: &fruits})
+	expectCode(t, err, tcp.StatusConflict)
+
+	// Догадки проверяются по алфавиту категории, из которой роздано слово,
+	// даже если категории комнаты уже нет в наборах (например, в старом снимке)
+	room.Lock()
+	room.Category = "удалённая"
+	room.Unlock()
+	if _, _, err := rc.MakeGuess("alice", room.ID, 'а'); err != nil {
+		t.Fatalf("guess must use the alphabet of the dealt word: %v", err)
+	}
+}
+
+func TestGameStateVisibility(t *testing.T) {
+	rc := newTestController(t, "alice", "eve", "sam")
+	room := startTestRoom(t, rc, "secret", "alice")
+	defer rc.closeRoom(room.ID)
+	if _, err := rc.JoinRoom(testContext(), "sam", room.ID, "", true); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := rc.MakeGuess("alice", room.ID, 'а'); err != nil {
+		t.Fatal(err)
+	}
+
+	states, err := rc.GetGameState("alice", room.ID)
+	if err != nil || len(states["alice"].Correct)+len(states["alice"].Wrong) != 1 {
+		t.Fatalf("player must see the full state, got %+v, %v", states["alice"], err)
+	}
+	states, err = rc.GetGameState("sam", room.ID)
+	if err != nil || len(states["alice"].Correct)+len(states["alice"].Wrong) != 0 {
+		t.Fatalf("spectator must see the masked state, got %+v, %v", states["alice"], err)
+	}
+
+	// Посторонний и бывший зритель состояние не видят
+	_, err = rc.GetGameState("eve", room.ID)
+	expectCode(t, err, tcp.StatusUnauthorized)
+	if err := rc.LeaveRoom("sam", room.ID); err != nil {
+		t.Fatal(err)
+	}
+	_, err = rc.GetGameState("sam", room.ID)
+	expectCode(t, err, tcp.StatusUnauthorized)
+}
+
+func TestScoresArePerRoom(t *testing.T) {
+	rc := newTestController(t, "alice", "bob")
+	first := startTestRoom(t, rc, "first", "alice", "bob")
+	defer rc.closeRoom(first.ID)
+	second := startTestRoom(t, rc, "second", "bob", "alice")
+	defer rc.closeRoom(second.ID)
+
+	word := []rune(first.StateManager.Snapshot()["alice"].Word)
+	outcome, _, err := rc.MakeGuess("alice", first.ID, word[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.RLock()
+	scored := first.Players["alice"].Score
+	first.RUnlock()
+	second.RLock()
+	untouched := second.Players["alice"].Score
+	second.RUnlock()
+	if scored != outcome.Points || untouched != 0 {
+		t.Fatalf("expected %d points only in the first room, got %d and %d", outcome.Points, scored, untouched)
+	}
+}
+
+func TestGuessCountsWhenNextRoundFails(t *testing.T) {
+	rc := newTestController(t, "alice")
+	rounds := 2
+	room := startTestRoomWith(t, rc, "aborted", domain.RoomOptions{Rounds: &rounds}, "alice")
+	defer rc.closeRoom(room.ID)
+
+	// Тег действует со следующего раунда, а слов с ним в категории нет
+	tag := "нет-такого-тега"
+	if _, err := rc.UpdateRoom(room.ID, "alice", nil, domain.RoomOptions{Tag: &tag}); err != nil {
+		t.Fatal(err)
+	}
+	word := room.StateManager.Snapshot()["alice"].Word
+	outcome, state, err := rc.GuessWord("alice", room.ID, word)
+	if err != nil {
+		t.Fatalf("the guess is already counted and must not fail: %v", err)
+	}
+	if outcome.Result != domain.GuessWon || !state.IsWon {
+		t.Fatalf("expected a won game, got %+v, %+v", outcome, state)
+	}
+	if room.RoomState != domain.GameOver {
+		t.Errorf("the match must be aborted, got %v", room.RoomState)
+	}
+	if match, _ := room.MatchState(); len(match.RoundScores) != 1 {
+		t.Errorf("the finished round must stay recorded, got %+v", match)
+	}
+}
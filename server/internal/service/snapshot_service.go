@@ -0,0 +1,47 @@
+package service
+
+import (
+	"hangman/internal/domain"
+	tcp "hangman/pkg/tcp-server"
+)
+
+type SnapshotService struct {
+	roomRepo     domain.IRoomRepository
+	snapshotRepo domain.ISnapshotRepository
+}
+
+func NewSnapshotService(roomRepo domain.IRoomRepository, snapshotRepo domain.ISnapshotRepository) *SnapshotService {
+	return &SnapshotService{
+		roomRepo:     roomRepo,
+		snapshotRepo: snapshotRepo,
+	}
+}
+
+// Save сохраняет все комнаты вместе с состоянием игр
+func (ss *SnapshotService) Save() error {
+	rooms := ss.roomRepo.GetAllRooms()
+	snapshots := make([]domain.RoomSnapshot, 0, len(rooms))
+	for _, room := range rooms {
+		snapshots = append(snapshots, room.Snapshot())
+	}
+	return ss.snapshotRepo.Save(snapshots)
+}
+
+// Restore восстанавливает комнаты из последнего снимка и возвращает их количество
+func (ss *SnapshotService) Restore(notificationSrv *tcp.NotificationServer) (int, error) {
+	snapshots, err := ss.snapshotRepo.Load()
+	if err != nil {
+		return 0, err
+	}
+
+	restored := 0
+	for _, snapshot := range snapshots {
+		room := domain.RestoreRoom(snapshot, notificationSrv)
+		room.UpdateActivity() // Иначе комнату сразу удалит CleanupRooms
+		if err := ss.roomRepo.AddRoom(room); err != nil {
+			return restored, err
+		}
+		restored++
+	}
+	return restored, nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"hangman/internal/domain"
+	"hangman/internal/repository"
+	ctx_repo "hangman/pkg/ctx-repo"
+	tcp "hangman/pkg/tcp-server"
+	"hangman/pkg/utils"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	rc := newTestController(t, "alice", "bob")
+	mode, rounds := string(domain.ModeTurnBased), 2
+	room := startTestRoomWith(t, rc, "saved", domain.RoomOptions{Mode: &mode, Rounds: &rounds}, "alice", "bob")
+	defer rc.closeRoom(room.ID)
+
+	// alice открывает букву и получает очки, ход переходит к bob
+	word := []rune(room.StateManager.Snapshot()["alice"].Word)
+	if outcome, _, err := rc.MakeGuess("alice", room.ID, word[0]); err != nil || outcome.Points <= 0 {
+		t.Fatalf("expected a scoring guess, got %+v, %v", outcome, err)
+	}
+	holder, _ := room.StateManager.CurrentTurn()
+	aliceState, _ := room.StateManager.GetState("alice")
+	room.Lock()
+	room.LastActivity = time.Now().Add(-time.Hour)
+	room.Unlock()
+
+	snapshots := repository.NewSnapshotRepository(filepath.Join(t.TempDir(), "snapshot.json"))
+	if err := NewSnapshotService(rc.roomRepo, snapshots).Save(); err != nil {
+		t.Fatal(err)
+	}
+	rooms := repository.NewRoomRepository()
+	logger := utils.NewCustomLogger(utils.LevelError)
+	if n, err := NewSnapshotService(rooms, snapshots).Restore(tcp.NewNotificationServer("", tcp.NewSessionManager(), logger)); err != nil || n != 1 {
+		t.Fatalf("expected one restored room, got %d, %v", n, err)
+	}
+	restored, err := rooms.GetRoomByID(room.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if restored.RoomState != domain.InProgress || restored.Mode != domain.ModeTurnBased || restored.Rounds != 2 {
+		t.Errorf("room settings lost: %v %+v", restored.RoomState, restored.RoomSettings)
+	}
+	if round, _ := restored.CurrentRound(); round != 1 {
+		t.Errorf("expected round 1, got %d", round)
+	}
+	if next, ok := restored.StateManager.CurrentTurn(); !ok || next != holder || next != "bob" {
+		t.Errorf("expected the turn to stay with %q, got %q", holder, next)
+	}
+	if restored.StateManager.Category() != "животные" {
+		t.Errorf("dealt category lost: %q", restored.StateManager.Category())
+	}
+	if state, _ := restored.StateManager.GetState("alice"); state.Score != aliceState.Score || state.WordProgress != aliceState.WordProgress {
+		t.Errorf("expected alice's state %+v, got %+v", aliceState, state)
+	}
+	if state, _ := restored.StateManager.GetState("bob"); state.Score != 0 || state.WordProgress != aliceState.WordProgress {
+		t.Errorf("bob must share the word with his own score, got %+v", state)
+	}
+
+	// Игроки возвращаются через JOIN_ROOM, а комнату не удалит очистка неактивных
+	if restored.IsPlayerActive("alice") || restored.IsPlayerActive("bob") {
+		t.Errorf("restored players must be disconnected")
+	}
+	if time.Since(restored.LastActivity) > time.Minute {
+		t.Errorf("restore must refresh activity, got %v", restored.LastActivity)
+	}
+}
+
+func TestRejoinRestoredWaitingRoom(t *testing.T) {
+	rc := newTestController(t, "alice", "bob")
+	category := "животные"
+	if _, err := rc.CreateRoom(testContext(), "alice", "lobby", "", domain.RoomOptions{Category: &category}); err != nil {
+		t.Fatal(err)
+	}
+	for _, username := range []string{"alice", "bob"} {
+		if _, err := rc.JoinRoom(testContext(), username, "lobby", "", false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	snapshots := repository.NewSnapshotRepository(filepath.Join(t.TempDir(), "snapshot.json"))
+	if err := NewSnapshotService(rc.roomRepo, snapshots).Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// После перезапуска комната ещё не начинала игру, и alice возвращается в неё
+	ctx, cancel := context.WithCancel(testContext())
+	defer cancel()
+	restarted := NewRoomController(repository.NewRoomRepository(), rc.playerRepo, rc.gameService, ctx_repo.NewCtxRepository())
+	if _, err := NewSnapshotService(restarted.roomRepo, snapshots).Restore(tcp.NewNotificationServer("", tcp.NewSessionManager(), utils.NewCustomLogger(utils.LevelError))); err != nil {
+		t.Fatal(err)
+	}
+	room, err := restarted.JoinRoom(ctx, "alice", "lobby", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if room.RoomState != domain.Waiting || !room.IsPlayerActive("alice") || room.IsPlayerActive("bob") {
+		t.Fatalf("alice must be reconnected and bob still away")
+	}
+
+	// Вернувшийся игрок снова отслеживается: при отключении он покидает комнату
+	cancel()
+	deadline := time.Now().Add(2 * time.Second)
+	for room.HasPlayer("alice") {
+		if time.Now().After(deadline) {
+			t.Fatal("alice must leave the waiting room after disconnecting")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"hangman/internal/domain"
+	"hangman/internal/events"
+	"hangman/pkg/utils"
+	"sync"
+	"time"
+)
+
+// timerTickInterval — как часто проверяются таймеры и рассылается TimerTick
+const timerTickInterval = time.Second
+
+// TimerScheduler держит не больше одного цикла таймеров на комнату
+type TimerScheduler struct {
+	mu      sync.Mutex
+	running map[string]*scheduledTimer
+}
+
+type scheduledTimer struct {
+	cancel context.CancelFunc
+}
+
+func NewTimerScheduler() *TimerScheduler {
+	return &TimerScheduler{running: make(map[string]*scheduledTimer)}
+}
+
+// Start запускает run в отдельной горутине, если для комнаты цикл ещё не идёт.
+// Контекст run отменяется через Stop.
+func (s *TimerScheduler) Start(roomID string, run func(ctx context.Context)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.running[roomID]; exists {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	timer := &scheduledTimer{cancel: cancel}
+	s.running[roomID] = timer
+
+	go func() {
+		defer s.finish(roomID, timer)
+		run(ctx)
+	}()
+}
+
+// Stop останавливает цикл таймеров комнаты
+func (s *TimerScheduler) Stop(roomID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if timer, exists := s.running[roomID]; exists {
+		timer.cancel()
+		delete(s.running, roomID)
+	}
+}
+
+// finish убирает завершившийся цикл, если на его месте ещё не запущен новый
+func (s *TimerScheduler) finish(roomID string, timer *scheduledTimer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	timer.cancel()
+	if s.running[roomID] == timer {
+		delete(s.running, roomID)
+	}
+}
+
+// startTimers запускает слежение за временем ходов и игр комнаты до конца матча
+func (rc *RoomController) startTimers(room *domain.Room) {
+	rc.timers.Start(room.ID, func(ctx context.Context) {
+		rc.runTimers(ctx, room)
+	})
+}
+
+// roundTimers — ограничения времени текущего раунда.
+// Настройки фиксируются в начале раунда и меняются только со следующего.
+type roundTimers struct {
+	round        int
+	stateManager *domain.GameStateManager
+	turnLimit    time.Duration
+	gameDeadline time.Time // Нулевое время — игра не ограничена
+}
+
+// runTimers раз в timerTickInterval проверяет время ходов и игры, пока комната в игре.
+// Цикл сам замечает начало нового раунда и выходит, когда матч окончен.
+func (rc *RoomController) runTimers(ctx context.Context, room *domain.Room) {
+	logger := utils.NewCustomLogger(utils.LevelInfo)
+	ticker := time.NewTicker(timerTickInterval)
+	defer ticker.Stop()
+
+	var current roundTimers
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			room.RLock()
+			state := room.RoomState
+			turnSeconds, gameSeconds := room.TurnSeconds, room.GameSeconds
+			room.RUnlock()
+			round, stateManager := room.CurrentRound()
+			if state != domain.InProgress || stateManager == nil {
+				return // Матч окончен
+			}
+			if round != current.round || stateManager != current.stateManager {
+				current = roundTimers{
+					round:        round,
+					stateManager: stateManager,
+					turnLimit:    time.Duration(turnSeconds) * time.Second,
+				}
+				if gameSeconds > 0 {
+					current.gameDeadline = now.Add(time.Duration(gameSeconds) * time.Second)
+				}
+			}
+			if err := rc.checkTimers(room, current, now); err != nil {
+				logger.Error(fmt.Sprintf("Room %s timers: %v", room.ID, err))
+			}
+		}
+	}
+}
+
+// checkTimers применяет штрафы за истёкшее время и рассылает оставшееся время
+func (rc *RoomController) checkTimers(room *domain.Room, timers roundTimers, now time.Time) error {
+	if timers.turnLimit == 0 && timers.gameDeadline.IsZero() {
+		return nil
+	}
+	if !timers.gameDeadline.IsZero() && !now.Before(timers.gameDeadline) {
+		players, err := rc.gameService.TimeoutGame(room)
+		if err != nil {
+			return err
+		}
+		// Закрыты все незавершённые игры, в том числе общее слово, — записываем каждого по разу
+		if err := rc.recordResults(timers.stateManager, players); err != nil {
+			return err
+		}
+		rc.advanceMatch(room)
+		return nil
+	}
+
+	tick := events.TimerTickEventPayload{}
+	if !timers.gameDeadline.IsZero() {
+		tick.GameSecondsLeft = secondsLeft(timers.gameDeadline.Sub(now))
+	}
+	finished := false
+	if timers.turnLimit > 0 {
+		tick.TurnSecondsLeft = make(map[string]int)
+		for username, started := range timers.stateManager.TurnClocks(now) {
+			left := timers.turnLimit - now.Sub(started)
+			if left > 0 {
+				tick.TurnSecondsLeft[string(username)] = secondsLeft(left)
+				continue
+			}
+			outcome, ok, err := rc.gameService.TimeoutTurn(room, username, started)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue // Игрок успел сходить
+			}
+			if outcome.IsGameOver() {
+				if err := rc.recordIfGameOver(room, timers.stateManager, string(username)); err != nil {
+					return err
+				}
+				finished = true
+			}
+		}
+	}
+	if finished {
+		// Раунд мог закончиться; время следующего раунда разошлёт следующий тик
+		rc.advanceMatch(room)
+		return nil
+	}
+	if tick.GameSecondsLeft == 0 && len(tick.TurnSecondsLeft) == 0 {
+		return nil // Хода ни от кого не ждут, а время игры не ограничено
+	}
+	return room.NotifyPlayers("TimerTick", tick)
+}
+
+// secondsLeft округляет оставшееся время вверх, чтобы ноль означал истёкший таймер
+func secondsLeft(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
@@ -12,12 +12,56 @@ type CheckUsernameResponse struct {
 	IsUnique bool `json:"is_unique"`
 }
 
+type RegisterRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type LoginResponse struct {
+	Username     string `json:"username"`
+	SessionToken string `json:"session_token"` // Токен сессии для подписки на уведомления
+}
+
 type CreateRoomRequest struct {
-	PlayerUsername string `json:"player_username"`
-	RoomID         string `json:"room_id"`
-	Password       string `json:"password"`
-	Category       string `json:"category"`
-	Difficulty     string `json:"difficulty"`
+	RoomID     string               `json:"room_id"`
+	Password   string               `json:"password"`
+	Category   string               `json:"category"`
+	Difficulty string               `json:"difficulty"`
+	Settings   *RoomSettingsRequest `json:"settings"` // Остальные настройки; отсутствующие поля — по умолчанию
+}
+
+// RoomSettingsRequest — настройки комнаты в CREATE_ROOM и UPDATE_ROOM.
+// Отсутствующее поле означает значение по умолчанию при создании и прежнее значение при обновлении.
+type RoomSettingsRequest struct {
+	Mode        *string `json:"mode"`         // classic, turn_based или race
+	HintMode    *string `json:"hint_mode"`    // letter или definition
+	Rounds      *int    `json:"rounds"`       // Число раундов в матче
+	MaxPlayers  *int    `json:"max_players"`  // Число мест для игроков
+	Private     *bool   `json:"private"`      // Не показывать комнату в GET_ALL_ROOMS
+	Attempts    *int    `json:"attempts"`     // Попыток на слово; 0 — по сложности
+	TurnSeconds *int    `json:"turn_seconds"` // Время на ход; 0 — без ограничения
+	GameSeconds *int    `json:"game_seconds"` // Время на слово в раунде; 0 — без ограничения
+	Tag         *string `json:"tag"`          // Тег слов; пустая строка снимает ограничение
+}
+
+// RoomSettingsDTO — действующие настройки комнаты
+type RoomSettingsDTO struct {
+	Category    string `json:"category"`
+	Difficulty  string `json:"difficulty"`
+	Mode        string `json:"mode"`
+	HintMode    string `json:"hint_mode"`
+	Rounds      int    `json:"rounds"`
+	MaxPlayers  int    `json:"max_players"`
+	Private     bool   `json:"private"`
+	Attempts    int    `json:"attempts"`
+	TurnSeconds int    `json:"turn_seconds"`
+	GameSeconds int    `json:"game_seconds"`
+	Tag         string `json:"tag"`
 }
 
 type CreateRoomResponse struct {
@@ -26,12 +70,11 @@ type CreateRoomResponse struct {
 }
 
 type UpdateRoomRequest struct {
-	PlayerUsername string  `json:"player_username"`
-	RoomID         string  `json:"room_id"`
-	Password       string  `json:"password"`
-	Category       *string `json:"category"`
-	Difficulty     *string `json:"difficulty"`
-	NewPassword    *string `json:"new_password"`
+	RoomID      string               `json:"room_id"`
+	Category    *string              `json:"category"`
+	Difficulty  *string              `json:"difficulty"`
+	NewPassword *string              `json:"new_password"`
+	Settings    *RoomSettingsRequest `json:"settings"`
 }
 
 type UpdateRoomResponse struct {
@@ -40,9 +83,7 @@ type UpdateRoomResponse struct {
 }
 
 type StartGameRequest struct {
-	PlayerUsername string `json:"player_username"`
-	RoomID         string `json:"room_id"`
-	Password       string `json:"password"`
+	RoomID string `json:"room_id"`
 }
 
 type StartGameResponse struct {
@@ -50,9 +91,9 @@ type StartGameResponse struct {
 }
 
 type JoinRoomRequest struct {
-	PlayerUsername string `json:"player_username"`
-	RoomID         string `json:"room_id"`
-	Password       string `json:"password"`
+	RoomID    string `json:"room_id"`
+	Password  string `json:"password"`
+	Spectator bool   `json:"spectator"` // Войти зрителем: смотреть без права хода
 }
 
 type PlayerDTO struct {
@@ -65,11 +106,15 @@ type JoinRoomResponse struct {
 	ID           string      `json:"id"`
 	Owner        string      `json:"owner"`
 	Players      []PlayerDTO `json:"players"`
+	Spectators   []string    `json:"spectators"`
 	LastActivity time.Time   `json:"last_activity"`
 	MaxPlayers   int         `json:"max_players"`
 	Password     string      `json:"password"`
 	Category     string      `json:"category"`
 	Difficulty   string      `json:"difficulty"`
+	Mode         string      `json:"mode"`
+	Rounds       int         `json:"rounds"`
+	HintMode     string      `json:"hint_mode"`
 	RoomState    string      `json:"state"`
 }
 
@@ -78,15 +123,24 @@ type GetRoomStateRequest struct {
 	Password string `json:"password"`
 }
 type GetRoomStateResponse struct {
-	Owner   string      `json:"owner"`
-	State   string      `json:"state"`
-	Players []PlayerDTO `json:"players"`
+	Owner      string          `json:"owner"`
+	State      string          `json:"state"`
+	Mode       string          `json:"mode"`
+	Players    []PlayerDTO     `json:"players"`
+	Spectators []string        `json:"spectators"`
+	Match      *MatchDTO       `json:"match,omitempty"`
+	Settings   RoomSettingsDTO `json:"settings"`
+}
+
+// MatchDTO описывает ход текущего матча
+type MatchDTO struct {
+	Rounds       int            `json:"rounds"`        // Всего раундов
+	CurrentRound int            `json:"current_round"` // Текущий раунд
+	Totals       map[string]int `json:"totals"`        // Сумма очков за завершённые раунды
 }
 
 type LeaveRoomRequest struct {
-	PlayerUsername string `json:"player_username"`
-	RoomID         string `json:"room_id"`
-	Password       string `json:"password"`
+	RoomID string `json:"room_id"`
 }
 
 type LeaveRoomResponse struct {
@@ -95,35 +149,69 @@ type LeaveRoomResponse struct {
 }
 
 type DeleteRoomRequest struct {
-	PlayerUsername string `json:"player_username"`
-	RoomID         string `json:"room_id"`
-	Password       string `json:"password"`
+	RoomID string `json:"room_id"`
 }
 
 type GuessLetterRequest struct {
-	PlayerUsername string `json:"player_username"`
-	Password       string `json:"password"`
-	RoomID         string `json:"room_id"`
-	Letter         string `json:"letter"`
+	RoomID string `json:"room_id"`
+	Letter string `json:"letter"`
+	Lang   string `json:"lang"` // Язык feedback: en или ru, по умолчанию en
 }
 
 type GuessLetterResponse struct {
 	PlayerUsername string `json:"player_username"`
 	IsCorrect      bool   `json:"is_correct"`
 	GameOver       bool   `json:"game_over"`
+	Result         string `json:"result"`        // correct, wrong, repeat, won или lost
+	Points         int    `json:"points"`        // Изменение счёта за ход
+	Positions      []int  `json:"positions"`     // Позиции, открытые ходом
+	AttemptsLeft   int    `json:"attempts_left"` // Остаток попыток
+	WordProgress   string `json:"word_progress"`
 	Feedback       string `json:"feedback"`
 }
 
-type GetGameStateRequest struct {
+type GuessWordRequest struct {
+	RoomID string `json:"room_id"`
+	Word   string `json:"word"`
+	Lang   string `json:"lang"` // Язык feedback: en или ru, по умолчанию en
+}
+
+type GuessWordResponse struct {
 	PlayerUsername string `json:"player_username"`
-	RoomID         string `json:"room_id"`
+	IsCorrect      bool   `json:"is_correct"`    // Слово отгадано — игра выиграна
+	GameOver       bool   `json:"game_over"`     // Игра завершена победой или поражением
+	Result         string `json:"result"`        // wrong, won или lost
+	Points         int    `json:"points"`        // Изменение счёта за ход
+	AttemptsLeft   int    `json:"attempts_left"` // Остаток попыток после штрафа
+	Score          int    `json:"score"`
+	Feedback       string `json:"feedback"`
+}
+
+type RequestHintRequest struct {
+	RoomID string `json:"room_id"`
+}
+
+type RequestHintResponse struct {
+	Type         string `json:"type"`             // letter или definition
+	Letter       string `json:"letter,omitempty"` // Открытая буква
+	Hint         string `json:"hint,omitempty"`   // Текст подсказки
+	Cost         int    `json:"cost"`             // Списанные очки
+	HintsLeft    int    `json:"hints_left"`
+	WordProgress string `json:"word_progress"`
+}
+
+type GetGameStateRequest struct {
+	RoomID string `json:"room_id"`
 }
 
 type PlayerGameStateDTO struct {
-	WordProgress string `json:"word_progress"` // Прогресс текущего слова
-	AttemptsLeft int    `json:"attempts_left"` // Остаток попыток
-	IsGameOver   bool   `json:"is_game_over"`  // Указатель на завершение игры
-	Score        int    `json:"score"`
+	WordProgress   string   `json:"word_progress"` // Прогресс текущего слова
+	AttemptsLeft   int      `json:"attempts_left"` // Остаток попыток
+	IsGameOver     bool     `json:"is_game_over"`  // Указатель на завершение игры
+	Score          int      `json:"score"`
+	IsTurn         bool     `json:"is_turn"`         // Сейчас ход игрока (режим turn_based)
+	CorrectLetters []string `json:"correct_letters"` // Названные буквы, которые есть в слове
+	WrongLetters   []string `json:"wrong_letters"`   // Названные буквы, которых в слове нет
 }
 
 type GetGameStateResponse struct {
@@ -132,11 +220,14 @@ type GetGameStateResponse struct {
 
 // RoomDTO описывает данные о комнате
 type RoomDTO struct {
-	ID           string `json:"id"`            // Уникальный идентификатор комнаты
-	Owner        string `json:"owner"`         // Имя владельца комнаты
-	PlayersCount int    `json:"players_count"` // Текущее количество игроков
-	MaxPlayers   int    `json:"max_players"`   // Максимальное количество игроков
-	GameState    string `json:"game_state"`
+	ID              string          `json:"id"`               // Уникальный идентификатор комнаты
+	Owner           string          `json:"owner"`            // Имя владельца комнаты
+	PlayersCount    int             `json:"players_count"`    // Текущее количество игроков
+	SpectatorsCount int             `json:"spectators_count"` // Количество зрителей
+	MaxPlayers      int             `json:"max_players"`      // Максимальное количество игроков
+	GameState       string          `json:"game_state"`
+	Mode            string          `json:"mode"` // Режим игры
+	Settings        RoomSettingsDTO `json:"settings"`
 	//IsOpen       bool      `json:"is_open"`       // Статус комнаты (открыта/закрыта)
 	LastActivity time.Time `json:"last_activity"` // Время последней активности
 }
@@ -147,8 +238,12 @@ type GetAllRoomsResponse struct {
 }
 
 type PlayerScoreDTO struct {
-	Username string `json:"username"`
-	Score    int    `json:"score"`
+	Username      string `json:"username"`
+	Score         int    `json:"score"`
+	GamesPlayed   int    `json:"games_played"`
+	GamesWon      int    `json:"games_won"`
+	CurrentStreak int    `json:"current_streak"`
+	BestStreak    int    `json:"best_streak"`
 }
 
 type GetLeaderBoardResponse struct {
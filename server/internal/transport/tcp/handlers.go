@@ -3,6 +3,7 @@ package tcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"hangman/internal/domain"
 	"hangman/internal/errs"
 	tcp_server "hangman/pkg/tcp-server"
@@ -12,15 +13,19 @@ import (
 
 type Handler struct {
 	RoomController domain.IRoomController
+	AuthService    domain.IAuthService
 }
 
-func NewHandler(controller domain.IRoomController) *Handler {
+func NewHandler(controller domain.IRoomController, authService domain.IAuthService) *Handler {
 	return &Handler{
 		RoomController: controller,
+		AuthService:    authService,
 	}
 }
 
 func (h *Handler) InitRoutes(srv *tcp_server.Server) {
+	srv.RegisterHandler("REGISTER", h.handleRegisterRequest)
+	srv.RegisterHandler("LOGIN", h.handleLoginRequest)
 	srv.RegisterHandler("CREATE_ROOM", h.handleCreateRoomRequest)
 	srv.RegisterHandler("UPDATE_ROOM", h.handleUpdateRoomRequest)
 	srv.RegisterHandler("START_GAME", h.handleStartGameRequest)
@@ -28,6 +33,8 @@ func (h *Handler) InitRoutes(srv *tcp_server.Server) {
 	srv.RegisterHandler("LEAVE_ROOM", h.handleLeaveRoomRequest)
 	srv.RegisterHandler("DELETE_ROOM", h.handleDeleteRoomRequest)
 	srv.RegisterHandler("GUESS_LETTER", h.handleGuessLetterRequest)
+	srv.RegisterHandler("GUESS_WORD", h.handleGuessWordRequest)
+	srv.RegisterHandler("REQUEST_HINT", h.handleRequestHintRequest)
 	srv.RegisterHandler("GET_GAME_STATE", h.handleGetGameStateRequest)
 	srv.RegisterHandler("GET_ALL_ROOMS", h.handleGetAllRoomsRequest)
 	srv.RegisterHandler("GET_LEADERBOARD", h.handleGetLeaderBoard)
@@ -54,15 +61,83 @@ func (h *Handler) handleCheckUsernameUniquenessRequest(ctx context.Context, mess
 	return responseBytes, nil
 }
 
+// currentPlayer возвращает имя игрока, авторизованного в сессии соединения
+func currentPlayer(ctx context.Context) (string, error) {
+	session, ok := tcp_server.GetSession(ctx)
+	if !ok || session.Identity() == "" {
+		return "", errs.NewError(tcp_server.StatusUnauthorized, "Not logged in. Please LOGIN first.")
+	}
+	return session.Identity(), nil
+}
+
+// handlerError сохраняет код прикладной ошибки, остальные ошибки считает внутренними
+func handlerError(err error) error {
+	var customErr *errs.Error
+	if errors.As(err, &customErr) {
+		return customErr
+	}
+	return errs.NewError(tcp_server.StatusInternalServerError, err.Error())
+}
+
+func (h *Handler) handleRegisterRequest(ctx context.Context, message []byte) ([]byte, error) {
+	var req RegisterRequest
+	if err := json.Unmarshal(message, &req); err != nil {
+		return nil, errs.NewError(tcp_server.StatusBadRequest, "Invalid REGISTER payload")
+	}
+
+	token, err := h.AuthService.Register(ctx, req.Username, req.Password)
+	if err != nil {
+		return nil, handlerError(err)
+	}
+
+	response := LoginResponse{
+		Username:     req.Username,
+		SessionToken: token,
+	}
+
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		return nil, errs.NewError(tcp_server.StatusInternalServerError, err.Error())
+	}
+	return responseBytes, nil
+}
+
+func (h *Handler) handleLoginRequest(ctx context.Context, message []byte) ([]byte, error) {
+	var req LoginRequest
+	if err := json.Unmarshal(message, &req); err != nil {
+		return nil, errs.NewError(tcp_server.StatusBadRequest, "Invalid LOGIN payload")
+	}
+
+	token, err := h.AuthService.Login(ctx, req.Username, req.Password)
+	if err != nil {
+		return nil, handlerError(err)
+	}
+
+	response := LoginResponse{
+		Username:     req.Username,
+		SessionToken: token,
+	}
+
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		return nil, errs.NewError(tcp_server.StatusInternalServerError, err.Error())
+	}
+	return responseBytes, nil
+}
+
 func (h *Handler) handleCreateRoomRequest(ctx context.Context, message []byte) ([]byte, error) {
 	var req CreateRoomRequest
 	if err := json.Unmarshal(message, &req); err != nil {
 		return nil, errs.NewError(tcp_server.StatusBadRequest, "Invalid CREATE_ROOM payload")
 	}
+	username, err := currentPlayer(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	room, err := h.RoomController.CreateRoom(ctx, req.PlayerUsername, req.RoomID, req.Password, req.Category, req.Difficulty)
+	room, err := h.RoomController.CreateRoom(ctx, username, req.RoomID, req.Password, req.Settings.options(&req.Category, &req.Difficulty))
 	if err != nil {
-		return nil, errs.NewError(tcp_server.StatusInternalServerError, err.Error())
+		return nil, handlerError(err)
 	}
 
 	response := CreateRoomResponse{
@@ -80,12 +155,15 @@ func (h *Handler) handleCreateRoomRequest(ctx context.Context, message []byte) (
 func (h *Handler) handleUpdateRoomRequest(ctx context.Context, message []byte) ([]byte, error) {
 	var req UpdateRoomRequest
 	if err := json.Unmarshal(message, &req); err != nil {
-		return nil, errs.NewError(tcp_server.StatusBadRequest, "Invalid CREATE_ROOM payload")
+		return nil, errs.NewError(tcp_server.StatusBadRequest, "Invalid UPDATE_ROOM payload")
 	}
-	clientKey := domain.NewClientKey(req.PlayerUsername, req.Password)
-	room, err := h.RoomController.UpdateRoom(req.RoomID, clientKey, req.NewPassword, req.Category, req.Difficulty)
+	username, err := currentPlayer(ctx)
 	if err != nil {
-		return nil, errs.NewError(tcp_server.StatusInternalServerError, err.Error())
+		return nil, err
+	}
+	room, err := h.RoomController.UpdateRoom(req.RoomID, username, req.NewPassword, req.Settings.options(req.Category, req.Difficulty))
+	if err != nil {
+		return nil, handlerError(err)
 	}
 
 	response := UpdateRoomResponse{
@@ -105,20 +183,28 @@ func (h *Handler) handleJoinRoomRequest(ctx context.Context, message []byte) ([]
 	if err := json.Unmarshal(message, &req); err != nil {
 		return nil, errs.NewError(tcp_server.StatusBadRequest, "Invalid JOIN_ROOM payload")
 	}
-	room, err := h.RoomController.JoinRoom(ctx, req.PlayerUsername, req.RoomID, req.Password)
+	username, err := currentPlayer(ctx)
 	if err != nil {
-		return nil, errs.NewError(tcp_server.StatusInternalServerError, err.Error())
+		return nil, err
+	}
+	room, err := h.RoomController.JoinRoom(ctx, username, req.RoomID, req.Password, req.Spectator)
+	if err != nil {
+		return nil, handlerError(err)
 	}
 	players := ConvertPlayersToSlice(room.Players)
 	response := JoinRoomResponse{
 		ID:           room.ID,
 		Owner:        *room.Owner,
 		Players:      players,
+		Spectators:   room.GetAllSpectators(),
 		LastActivity: room.LastActivity,
 		MaxPlayers:   room.MaxPlayers,
 		Password:     room.Password,
 		Category:     room.Category,
 		Difficulty:   room.Difficulty,
+		Mode:         string(room.Mode),
+		Rounds:       room.Rounds,
+		HintMode:     string(room.HintMode),
 		RoomState:    string(room.RoomState),
 	}
 	responseBytes, err := json.Marshal(response)
@@ -133,11 +219,14 @@ func (h *Handler) handleLeaveRoomRequest(ctx context.Context, message []byte) ([
 	if err := json.Unmarshal(message, &req); err != nil {
 		return nil, errs.NewError(tcp_server.StatusBadRequest, "Invalid LEAVE_ROOM payload")
 	}
-	clientKey := domain.NewClientKey(req.PlayerUsername, req.Password)
+	username, err := currentPlayer(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	err := h.RoomController.LeaveRoom(clientKey, req.RoomID)
+	err = h.RoomController.LeaveRoom(username, req.RoomID)
 	if err != nil {
-		return nil, errs.NewError(tcp_server.StatusInternalServerError, err.Error())
+		return nil, handlerError(err)
 	}
 
 	response := LeaveRoomResponse{
@@ -157,10 +246,13 @@ func (h *Handler) handleStartGameRequest(ctx context.Context, message []byte) ([
 	if err := json.Unmarshal(message, &req); err != nil {
 		return nil, errs.NewError(tcp_server.StatusBadRequest, "Invalid START_GAME payload")
 	}
-	clientKey := domain.NewClientKey(req.PlayerUsername, req.Password)
-	err := h.RoomController.StartGame(clientKey, req.RoomID)
+	username, err := currentPlayer(ctx)
 	if err != nil {
-		return nil, errs.NewError(tcp_server.StatusInternalServerError, err.Error())
+		return nil, err
+	}
+	err = h.RoomController.StartGame(username, req.RoomID)
+	if err != nil {
+		return nil, handlerError(err)
 	}
 
 	response := StartGameResponse{Message: "Game started successfully"}
@@ -177,10 +269,13 @@ func (h *Handler) handleDeleteRoomRequest(ctx context.Context, message []byte) (
 	if err := json.Unmarshal(message, &req); err != nil {
 		return nil, errs.NewError(tcp_server.StatusBadRequest, "Invalid DELETE_ROOM payload")
 	}
-	clientKey := domain.NewClientKey(req.PlayerUsername, req.Password)
-	err := h.RoomController.DeleteRoom(clientKey, req.RoomID)
+	username, err := currentPlayer(ctx)
 	if err != nil {
-		return nil, errs.NewError(tcp_server.StatusInternalServerError, err.Error())
+		return nil, err
+	}
+	err = h.RoomController.DeleteRoom(username, req.RoomID)
+	if err != nil {
+		return nil, handlerError(err)
 	}
 	response := map[string]string{"message": "Room deleted successfully"}
 
@@ -196,29 +291,100 @@ func (h *Handler) handleGuessLetterRequest(ctx context.Context, message []byte)
 	if err := json.Unmarshal(message, &req); err != nil {
 		return nil, errs.NewError(tcp_server.StatusBadRequest, "Invalid GUESS_LETTER payload")
 	}
+	username, err := currentPlayer(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	// Проверяем длину введённого символа
 	if utf8.RuneCountInString(req.Letter) != 1 {
 		return nil, errs.NewError(tcp_server.StatusBadRequest, "Invalid letter input. Please provide a single character.")
 	}
-	clientKey := domain.NewClientKey(req.PlayerUsername, req.Password)
-	isCorrect, feedback, err := h.RoomController.MakeGuess(clientKey, req.RoomID, []rune(req.Letter)[0])
+	outcome, _, err := h.RoomController.MakeGuess(username, req.RoomID, []rune(req.Letter)[0])
 	if err != nil {
-		return nil, errs.NewError(tcp_server.StatusInternalServerError, err.Error())
+		return nil, handlerError(err)
 	}
 
 	// Формируем успешный ответ
 	response := GuessLetterResponse{
-		PlayerUsername: req.PlayerUsername,
-		IsCorrect:      isCorrect,
-		Feedback:       feedback,
+		PlayerUsername: username,
+		IsCorrect:      outcome.Result == domain.GuessCorrect || outcome.Result == domain.GuessWon,
+		GameOver:       outcome.IsGameOver(),
+		Result:         string(outcome.Result),
+		Points:         outcome.Points,
+		Positions:      positions(outcome.Positions),
+		AttemptsLeft:   outcome.AttemptsLeft,
+		WordProgress:   outcome.WordProgress,
+		Feedback:       guessFeedback(outcome, req.Lang),
+	}
+
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		return nil, errs.NewError(tcp_server.StatusInternalServerError, err.Error())
+	}
+	return responseBytes, nil
+}
+
+func (h *Handler) handleGuessWordRequest(ctx context.Context, message []byte) ([]byte, error) {
+	var req GuessWordRequest
+	if err := json.Unmarshal(message, &req); err != nil {
+		return nil, errs.NewError(tcp_server.StatusBadRequest, "Invalid GUESS_WORD payload")
+	}
+	username, err := currentPlayer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(req.Word) == "" {
+		return nil, errs.NewError(tcp_server.StatusBadRequest, "Invalid word input. Please provide a word.")
+	}
+	outcome, state, err := h.RoomController.GuessWord(username, req.RoomID, req.Word)
+	if err != nil {
+		return nil, handlerError(err)
+	}
+
+	response := GuessWordResponse{
+		PlayerUsername: username,
+		IsCorrect:      outcome.Result == domain.GuessWon,
+		GameOver:       outcome.IsGameOver(),
+		Result:         string(outcome.Result),
+		Points:         outcome.Points,
+		AttemptsLeft:   outcome.AttemptsLeft,
+		Score:          state.Score,
+		Feedback:       guessFeedback(outcome, req.Lang),
 	}
 
-	// Проверяем окончание игры
-	if strings.Contains(feedback, "Game Over") || strings.Contains(feedback, "Congratulations") {
-		response.GameOver = true
-	} else {
-		response.GameOver = false
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		return nil, errs.NewError(tcp_server.StatusInternalServerError, err.Error())
+	}
+	return responseBytes, nil
+}
+
+func (h *Handler) handleRequestHintRequest(ctx context.Context, message []byte) ([]byte, error) {
+	var req RequestHintRequest
+	if err := json.Unmarshal(message, &req); err != nil {
+		return nil, errs.NewError(tcp_server.StatusBadRequest, "Invalid REQUEST_HINT payload")
+	}
+	username, err := currentPlayer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hint, err := h.RoomController.RequestHint(username, req.RoomID)
+	if err != nil {
+		return nil, handlerError(err)
+	}
+
+	response := RequestHintResponse{
+		Type:         string(hint.Kind),
+		Hint:         hint.Text,
+		Cost:         hint.Cost,
+		HintsLeft:    hint.HintsLeft,
+		WordProgress: hint.WordProgress,
+	}
+	if hint.Kind == domain.HintLetter {
+		response.Letter = string(hint.Letter)
 	}
 
 	responseBytes, err := json.Marshal(response)
@@ -233,21 +399,28 @@ func (h *Handler) handleGetGameStateRequest(ctx context.Context, message []byte)
 	if err := json.Unmarshal(message, &dto); err != nil {
 		return nil, errs.NewError(tcp_server.StatusBadRequest, "Invalid GET_GAME_STATE payload")
 	}
+	username, err := currentPlayer(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	// Получаем состояния игры для всех игроков
-	playerGameStates, err := h.RoomController.GetGameState(dto.RoomID)
+	playerGameStates, err := h.RoomController.GetGameState(username, dto.RoomID)
 	if err != nil {
-		return nil, errs.NewError(tcp_server.StatusInternalServerError, err.Error())
+		return nil, handlerError(err)
 	}
 
 	// Преобразуем данные из map[string]*GameState в map[string]*PlayerGameStateDTO
 	players := make(map[string]*PlayerGameStateDTO)
 	for username, state := range playerGameStates {
 		players[username] = &PlayerGameStateDTO{
-			WordProgress: state.WordProgress,
-			AttemptsLeft: state.AttemptsLeft,
-			IsGameOver:   state.IsGameOver,
-			Score:        state.Score,
+			WordProgress:   state.WordProgress,
+			AttemptsLeft:   state.AttemptsLeft,
+			IsGameOver:     state.IsGameOver,
+			Score:          state.Score,
+			IsTurn:         state.IsTurn,
+			CorrectLetters: letters(state.Correct),
+			WrongLetters:   letters(state.Wrong),
 		}
 	}
 
@@ -271,19 +444,37 @@ func (h *Handler) handleGetRoomStateRequest(ctx context.Context, message []byte)
 	if err := json.Unmarshal(message, &req); err != nil {
 		return nil, errs.NewError(tcp_server.StatusBadRequest, "Invalid GET_ROOM_STATE payload")
 	}
+	username, err := currentPlayer(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	// Получаем состояние комнаты через контроллер
-	room, err := h.RoomController.GetRoomState(req.RoomID, req.Password)
+	room, err := h.RoomController.GetRoomState(username, req.RoomID, req.Password)
 	if err != nil {
-		return nil, errs.NewError(tcp_server.StatusInternalServerError, err.Error())
+		return nil, handlerError(err)
 	}
 	roomState := string(room.RoomState)
 	// Формируем ответ DTO
 	players := ConvertPlayersToSlice(room.Players)
 	response := GetRoomStateResponse{
-		Owner:   *room.Owner,
-		Players: players,
-		State:   roomState,
+		Owner:      *room.Owner,
+		Players:    players,
+		Spectators: room.GetAllSpectators(),
+		State:      roomState,
+		Mode:       string(room.Mode),
+		Settings:   roomSettings(room.RoomSettings),
+	}
+	if match, ok := room.MatchState(); ok {
+		totals := make(map[string]int, len(match.Totals))
+		for username, total := range match.Totals {
+			totals[string(username)] = total
+		}
+		response.Match = &MatchDTO{
+			Rounds:       match.Rounds,
+			CurrentRound: match.CurrentRound,
+			Totals:       totals,
+		}
 	}
 
 	// Сериализуем ответ в JSON
@@ -304,12 +495,15 @@ func (h *Handler) handleGetAllRoomsRequest(ctx context.Context, message []byte)
 	roomDTOs := make([]RoomDTO, len(rooms))
 	for i, room := range rooms {
 		roomDTOs[i] = RoomDTO{
-			ID:           room.ID,
-			Owner:        *room.Owner,
-			PlayersCount: room.GetPlayerCount(),
-			MaxPlayers:   room.MaxPlayers,
-			LastActivity: room.LastActivity,
-			GameState:    string(room.RoomState),
+			ID:              room.ID,
+			Owner:           *room.Owner,
+			PlayersCount:    room.GetPlayerCount(),
+			SpectatorsCount: len(room.GetAllSpectators()),
+			MaxPlayers:      room.MaxPlayers,
+			LastActivity:    room.LastActivity,
+			GameState:       string(room.RoomState),
+			Mode:            string(room.Mode),
+			Settings:        roomSettings(room.RoomSettings),
 		}
 	}
 
@@ -326,17 +520,21 @@ func (h *Handler) handleGetAllRoomsRequest(ctx context.Context, message []byte)
 }
 
 func (h *Handler) handleGetLeaderBoard(ctx context.Context, message []byte) ([]byte, error) {
-	// Получаем данные о пользователях и их очках
-	userScores, err := h.RoomController.GetLeaderboard()
+	// Получаем статистику игроков за всё время, уже отсортированную по очкам
+	leaderboard, err := h.RoomController.GetLeaderboard()
 	if err != nil {
 		return nil, errs.NewError(tcp_server.StatusInternalServerError, "Failed to fetch Leaderboard")
 	}
 	// Формируем DTO
-	playerDTOs := make([]PlayerScoreDTO, 0, len(userScores))
-	for username, score := range userScores {
+	playerDTOs := make([]PlayerScoreDTO, 0, len(leaderboard))
+	for _, stats := range leaderboard {
 		playerDTOs = append(playerDTOs, PlayerScoreDTO{
-			Username: username,
-			Score:    score,
+			Username:      stats.Username,
+			Score:         stats.Score,
+			GamesPlayed:   stats.GamesPlayed,
+			GamesWon:      stats.GamesWon,
+			CurrentStreak: stats.CurrentStreak,
+			BestStreak:    stats.BestStreak,
 		})
 	}
 
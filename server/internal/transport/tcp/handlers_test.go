@@ -0,0 +1,42 @@
+package tcp
+
+import (
+	"context"
+	"errors"
+	"hangman/internal/errs"
+	tcp_server "hangman/pkg/tcp-server"
+	"testing"
+)
+
+func TestCommandsRequireLogin(t *testing.T) {
+	h := NewHandler(nil, nil)
+	session, err := tcp_server.NewSessionManager().Issue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := tcp_server.SetSession(context.Background(), session)
+
+	handlers := map[string]tcp_server.HandleFunc{
+		"CREATE_ROOM":    h.handleCreateRoomRequest,
+		"UPDATE_ROOM":    h.handleUpdateRoomRequest,
+		"JOIN_ROOM":      h.handleJoinRoomRequest,
+		"LEAVE_ROOM":     h.handleLeaveRoomRequest,
+		"START_GAME":     h.handleStartGameRequest,
+		"DELETE_ROOM":    h.handleDeleteRoomRequest,
+		"GUESS_LETTER":   h.handleGuessLetterRequest,
+		"GUESS_WORD":     h.handleGuessWordRequest,
+		"REQUEST_HINT":   h.handleRequestHintRequest,
+		"GET_GAME_STATE": h.handleGetGameStateRequest,
+		"GET_ROOM_STATE": h.handleGetRoomStateRequest,
+	}
+	for command, handler := range handlers {
+		t.Run(command, func(t *testing.T) {
+			// Контроллер не задан: до него запрос без входа дойти не должен
+			_, err := handler(ctx, []byte(`{"room_id":"r","letter":"а","word":"кот"}`))
+			var appErr *errs.Error
+			if !errors.As(err, &appErr) || appErr.Code != tcp_server.StatusUnauthorized {
+				t.Fatalf("expected 4003 without login, got %v", err)
+			}
+		})
+	}
+}
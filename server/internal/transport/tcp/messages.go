@@ -0,0 +1,48 @@
+package tcp
+
+import (
+	"fmt"
+	"hangman/internal/domain"
+)
+
+// defaultLang — язык ответов, если клиент его не указал.
+const defaultLang = "en"
+
+// guessMessages — тексты исходов хода по языкам. %s заменяется загаданным словом.
+var guessMessages = map[string]map[domain.GuessResult]string{
+	"en": {
+		domain.GuessCorrect: "Correct guess!",
+		domain.GuessWrong:   "Wrong guess!",
+		domain.GuessRepeat:  "You have already tried this letter.",
+		domain.GuessWon:     "Congratulations! You guessed the word: %s",
+		domain.GuessLost:    "Game Over! The word was: %s",
+	},
+	"ru": {
+		domain.GuessCorrect: "Есть такая буква!",
+		domain.GuessWrong:   "Нет такой буквы!",
+		domain.GuessRepeat:  "Эта буква уже была.",
+		domain.GuessWon:     "Поздравляем! Вы отгадали слово: %s",
+		domain.GuessLost:    "Игра окончена! Было загадано слово: %s",
+	},
+}
+
+// wrongWordMessages — текст неверного слова, отличный от неверной буквы.
+var wrongWordMessages = map[string]string{
+	"en": "Wrong word!",
+	"ru": "Неверное слово!",
+}
+
+// guessFeedback возвращает текст исхода хода на языке клиента.
+func guessFeedback(outcome domain.GuessOutcome, lang string) string {
+	if _, ok := guessMessages[lang]; !ok {
+		lang = defaultLang
+	}
+	if outcome.Result == domain.GuessWrong && outcome.Word != "" {
+		return wrongWordMessages[lang]
+	}
+	message := guessMessages[lang][outcome.Result]
+	if outcome.IsGameOver() {
+		return fmt.Sprintf(message, outcome.Answer)
+	}
+	return message
+}
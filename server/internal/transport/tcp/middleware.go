@@ -0,0 +1,27 @@
+package tcp
+
+import (
+	"context"
+	"hangman/internal/domain"
+	tcp_server "hangman/pkg/tcp-server"
+)
+
+// TrackActivity отмечает активность авторизованного игрока при каждой его команде,
+// чтобы мониторинг соединений не счёл его неактивным.
+func TrackActivity(playerRepo domain.IPlayerRepository) tcp_server.Middleware {
+	return func(next tcp_server.HandleFunc) tcp_server.HandleFunc {
+		return func(ctx context.Context, message []byte) ([]byte, error) {
+			if username, err := currentPlayer(ctx); err == nil {
+				if err := playerRepo.UpdatePlayerActivity(username); err != nil {
+					// Мониторинг удалил долго молчавшего игрока, но сессия соединения ещё действует:
+					// возвращаем игрока по ней, чтобы не требовать повторного входа
+					conn, _ := tcp_server.GetConn(ctx)
+					if err := playerRepo.AddPlayer(domain.NewPlayer(&conn, username, 0)); err != nil {
+						return nil, err
+					}
+				}
+			}
+			return next(ctx, message)
+		}
+	}
+}
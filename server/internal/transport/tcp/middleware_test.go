@@ -0,0 +1,30 @@
+package tcp
+
+import (
+	"context"
+	"hangman/internal/repository"
+	tcp_server "hangman/pkg/tcp-server"
+	"testing"
+)
+
+func TestTrackActivityRestoresIdlePlayer(t *testing.T) {
+	playerRepo := repository.NewPlayerRepository()
+	session, err := tcp_server.NewSessionManager().Issue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	session.Bind("alice")
+	ctx := tcp_server.SetSession(context.Background(), session)
+
+	// Мониторинг уже удалил alice из репозитория, а её соединение живо
+	handler := TrackActivity(playerRepo)(func(context.Context, []byte) ([]byte, error) {
+		player, err := playerRepo.GetPlayerByUsername("alice")
+		if err != nil {
+			return nil, err
+		}
+		return []byte(player.Username), nil
+	})
+	if _, err := handler(ctx, nil); err != nil {
+		t.Fatalf("command of a logged in player must not fail after idle cleanup: %v", err)
+	}
+}
@@ -24,3 +24,55 @@ func GetPlayerIp(connAddr string) (string, error) {
 	}
 	return connIp, nil
 }
+
+// letters переводит буквы в строки для JSON; пустой набор остаётся пустым массивом
+func letters(runes []rune) []string {
+	result := make([]string, 0, len(runes))
+	for _, r := range runes {
+		result = append(result, string(r))
+	}
+	return result
+}
+
+// positions гарантирует пустой массив вместо null в JSON
+func positions(indexes []int) []int {
+	if indexes == nil {
+		return []int{}
+	}
+	return indexes
+}
+
+// roomSettings переводит настройки комнаты в DTO ответа
+func roomSettings(settings domain.RoomSettings) RoomSettingsDTO {
+	return RoomSettingsDTO{
+		Category:    settings.Category,
+		Difficulty:  settings.Difficulty,
+		Mode:        string(settings.Mode),
+		HintMode:    string(settings.HintMode),
+		Rounds:      settings.Rounds,
+		MaxPlayers:  settings.MaxPlayers,
+		Private:     settings.Private,
+		Attempts:    settings.Attempts,
+		TurnSeconds: settings.TurnSeconds,
+		GameSeconds: settings.GameSeconds,
+		Tag:         settings.Tag,
+	}
+}
+
+// options собирает настройки из запроса; категория и сложность передаются отдельно,
+// потому что клиент присылает их на верхнем уровне запроса
+func (s *RoomSettingsRequest) options(category, difficulty *string) domain.RoomOptions {
+	options := domain.RoomOptions{Category: category, Difficulty: difficulty}
+	if s != nil {
+		options.Mode = s.Mode
+		options.HintMode = s.HintMode
+		options.Rounds = s.Rounds
+		options.MaxPlayers = s.MaxPlayers
+		options.Private = s.Private
+		options.Attempts = s.Attempts
+		options.TurnSeconds = s.TurnSeconds
+		options.GameSeconds = s.GameSeconds
+		options.Tag = s.Tag
+	}
+	return options
+}
@@ -17,6 +17,8 @@ const (
 	LoggerKey             ctxKey = "logger"
 	CancelKey             ctxKey = "cancel"
 	NotificationServerKey ctxKey = "notificationServer"
+	SessionKey            ctxKey = "session"
+	CommandKey            ctxKey = "command"
 )
 
 // SetConn устанавливает соединение в контекст.
@@ -62,3 +64,25 @@ func GetNotificationServer(ctx context.Context) (*NotificationServer, bool) {
 	notificationServer, ok := ctx.Value(NotificationServerKey).(*NotificationServer)
 	return notificationServer, ok
 }
+
+// SetSession устанавливает сессию соединения в контекст.
+func SetSession(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, SessionKey, session)
+}
+
+// GetSession извлекает сессию соединения из контекста.
+func GetSession(ctx context.Context) (*Session, bool) {
+	session, ok := ctx.Value(SessionKey).(*Session)
+	return session, ok
+}
+
+// SetCommand устанавливает имя обрабатываемой команды в контекст.
+func SetCommand(ctx context.Context, command string) context.Context {
+	return context.WithValue(ctx, CommandKey, command)
+}
+
+// GetCommand извлекает имя обрабатываемой команды из контекста.
+func GetCommand(ctx context.Context) (string, bool) {
+	command, ok := ctx.Value(CommandKey).(string)
+	return command, ok
+}
@@ -0,0 +1,74 @@
+package tcp_server
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// frameHeaderSize — размер заголовка кадра с длиной тела.
+const frameHeaderSize = 4
+
+// ErrFrameTooLarge возвращается, если длина кадра превышает допустимую.
+var ErrFrameTooLarge = errors.New("frame exceeds maximum size")
+
+// readMessage читает кадр целиком: заголовок длины и тело.
+func readMessage(conn net.Conn, maxSize int) ([]byte, error) {
+	length, err := readHeader(conn, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	return readBody(conn, length)
+}
+
+// readHeader читает заголовок кадра и проверяет длину тела.
+// Короткие чтения TCP дочитываются через io.ReadFull.
+func readHeader(conn net.Conn, maxSize int) (uint32, error) {
+	header := make([]byte, frameHeaderSize)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return 0, err
+	}
+
+	// Преобразование заголовка в длину сообщения
+	length := binary.BigEndian.Uint32(header)
+	if maxSize > 0 && length > uint32(maxSize) {
+		return 0, fmt.Errorf("%w: %d > %d bytes", ErrFrameTooLarge, length, maxSize)
+	}
+	return length, nil
+}
+
+// readBody читает тело кадра указанной длины.
+func readBody(conn net.Conn, length uint32) ([]byte, error) {
+	message := make([]byte, length)
+	if _, err := io.ReadFull(conn, message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// writeMessage отправляет сообщение с заголовком длины одной записью.
+func writeMessage(conn net.Conn, message []byte) error {
+	frame := make([]byte, frameHeaderSize+len(message))
+	binary.BigEndian.PutUint32(frame, uint32(len(message)))
+	copy(frame[frameHeaderSize:], message)
+
+	_, err := conn.Write(frame)
+	return err
+}
+
+// setDeadline выставляет дедлайн через timeout от текущего момента; ноль снимает дедлайн.
+func setDeadline(set func(time.Time) error, timeout time.Duration) error {
+	if timeout <= 0 {
+		return set(time.Time{})
+	}
+	return set(time.Now().Add(timeout))
+}
+
+// isTimeout проверяет, что ошибка вызвана истечением дедлайна.
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
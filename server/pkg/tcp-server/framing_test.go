@@ -0,0 +1,67 @@
+package tcp_server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+)
+
+func frame(body []byte) []byte {
+	buf := make([]byte, frameHeaderSize+len(body))
+	binary.BigEndian.PutUint32(buf, uint32(len(body)))
+	copy(buf[frameHeaderSize:], body)
+	return buf
+}
+
+func TestReadMessageShortReads(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	body := bytes.Repeat([]byte("x"), 100)
+	go func() {
+		// Кадр приходит по одному байту
+		for _, b := range frame(body) {
+			client.Write([]byte{b})
+		}
+	}()
+
+	message, err := readMessage(server, 1024)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(message, body) {
+		t.Fatalf("got %d bytes, want %d", len(message), len(body))
+	}
+}
+
+func TestReadMessageTooLarge(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	go client.Write(frame(make([]byte, 10)))
+
+	_, err := readMessage(server, 8)
+	if !errors.Is(err, ErrFrameTooLarge) {
+		t.Fatalf("expected ErrFrameTooLarge, got %v", err)
+	}
+}
+
+func TestWriteMessageRoundTrip(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	go writeMessage(client, []byte("hello"))
+
+	message, err := readMessage(server, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(message) != "hello" {
+		t.Fatalf("got %q", message)
+	}
+}
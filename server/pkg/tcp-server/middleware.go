@@ -0,0 +1,104 @@
+package tcp_server
+
+import (
+	"context"
+	"fmt"
+	"hangman/internal/errs"
+	"runtime/debug"
+	"time"
+)
+
+// Middleware оборачивает обработчик команды дополнительной логикой.
+type Middleware func(next HandleFunc) HandleFunc
+
+// Use добавляет middleware к цепочке обработки всех команд.
+// Middleware выполняются в порядке добавления: первый добавленный — самый внешний.
+func (s *Server) Use(middlewares ...Middleware) {
+	s.middlewares = append(s.middlewares, middlewares...)
+}
+
+// chain оборачивает обработчик всеми зарегистрированными middleware.
+func (s *Server) chain(handler HandleFunc) HandleFunc {
+	for i := len(s.middlewares) - 1; i >= 0; i-- {
+		handler = s.middlewares[i](handler)
+	}
+	return handler
+}
+
+// unknownCommand отвечает на команды без обработчика.
+// Такие запросы тоже проходят через middleware (логирование, ограничения).
+func unknownCommand(context.Context, []byte) ([]byte, error) {
+	return nil, errs.NewError(StatusNotFound, "Unknown command")
+}
+
+// Recovery перехватывает панику в обработчике и превращает её во внутреннюю ошибку.
+func Recovery(logger ILogger) Middleware {
+	return func(next HandleFunc) HandleFunc {
+		return func(ctx context.Context, message []byte) (resp []byte, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					command, _ := GetCommand(ctx)
+					logger.Error(fmt.Sprintf("Panic in %s handler: %v\n%s", command, r, debug.Stack()))
+					resp, err = nil, errs.NewError(StatusInternalServerError, "Internal server error")
+				}
+			}()
+			return next(ctx, message)
+		}
+	}
+}
+
+// Logging пишет в журнал команду, размер запроса, длительность и ошибку обработчика.
+func Logging(logger ILogger) Middleware {
+	return func(next HandleFunc) HandleFunc {
+		return func(ctx context.Context, message []byte) ([]byte, error) {
+			command, _ := GetCommand(ctx)
+			logger.Info(fmt.Sprintf("Request: Command: %s, PayloadSize: %d bytes", command, len(message)))
+			logger.Debug(fmt.Sprintf("Request: Command: %s, Payload: %s", command, string(message)))
+
+			start := time.Now()
+			resp, err := next(ctx, message)
+			if err != nil {
+				logger.Error(fmt.Sprintf("Command %s failed in %v: %v", command, time.Since(start), err))
+				return nil, err
+			}
+			logger.Debug(fmt.Sprintf("Response: Command: %s, Duration: %v, Payload: %s", command, time.Since(start), resp))
+			return resp, nil
+		}
+	}
+}
+
+// Timeout ограничивает время ответа на перечисленные команды, у каждой своё.
+// Остальные команды выполняются без ограничения: их изменения нельзя отменить на полпути,
+// а отказ по времени ввёл бы клиента в заблуждение. Поэтому ограничивать стоит команды чтения.
+// Контекст обработчика не отменяется: от него наследуются контексты участия в комнатах,
+// обработчик просто дорабатывает в фоне. Recovery следует подключать после Timeout.
+func Timeout(limits map[string]time.Duration) Middleware {
+	return func(next HandleFunc) HandleFunc {
+		return func(ctx context.Context, message []byte) ([]byte, error) {
+			command, _ := GetCommand(ctx)
+			limit := limits[command]
+			if limit <= 0 {
+				return next(ctx, message)
+			}
+
+			type result struct {
+				resp []byte
+				err  error
+			}
+			done := make(chan result, 1)
+			go func() {
+				resp, err := next(ctx, message)
+				done <- result{resp, err}
+			}()
+
+			timer := time.NewTimer(limit)
+			defer timer.Stop()
+			select {
+			case r := <-done:
+				return r.resp, r.err
+			case <-timer.C:
+				return nil, errs.NewError(StatusRequestTimeout, fmt.Sprintf("%s timed out after %v", command, limit))
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package tcp_server
+
+import (
+	"context"
+	"errors"
+	"hangman/internal/errs"
+	"testing"
+	"time"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Info(string)    {}
+func (nopLogger) Warning(string) {}
+func (nopLogger) Error(string)   {}
+func (nopLogger) Debug(string)   {}
+func (nopLogger) Fatal(string)   {}
+
+func TestMiddlewareOrder(t *testing.T) {
+	s := &Server{handlers: make(map[string]HandleFunc), logger: nopLogger{}}
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next HandleFunc) HandleFunc {
+			return func(ctx context.Context, message []byte) ([]byte, error) {
+				calls = append(calls, name)
+				return next(ctx, message)
+			}
+		}
+	}
+	s.Use(trace("first"), trace("second"))
+	s.RegisterHandler("PING", func(ctx context.Context, _ []byte) ([]byte, error) {
+		command, _ := GetCommand(ctx)
+		calls = append(calls, command)
+		return nil, nil
+	})
+
+	s.dispatch(context.Background(), "PING", nil)
+	if len(calls) != 3 || calls[0] != "first" || calls[1] != "second" || calls[2] != "PING" {
+		t.Fatalf("unexpected call order: %v", calls)
+	}
+}
+
+func TestRecoveryAndTimeout(t *testing.T) {
+	panicking := Recovery(nopLogger{})(func(context.Context, []byte) ([]byte, error) {
+		panic("boom")
+	})
+	_, err := panicking(context.Background(), nil)
+	var customErr *errs.Error
+	if !errors.As(err, &customErr) || customErr.Code != StatusInternalServerError {
+		t.Fatalf("expected internal error, got %v", err)
+	}
+
+	limits := Timeout(map[string]time.Duration{"GET_ROOM_STATE": 10 * time.Millisecond})
+	slow := limits(func(context.Context, []byte) ([]byte, error) {
+		time.Sleep(50 * time.Millisecond)
+		return []byte("done"), nil
+	})
+	_, err = slow(SetCommand(context.Background(), "GET_ROOM_STATE"), nil)
+	if !errors.As(err, &customErr) || customErr.Code != StatusRequestTimeout {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+
+	// Команды без ограничения дожидаются настоящего ответа
+	if resp, err := slow(SetCommand(context.Background(), "JOIN_ROOM"), nil); err != nil || string(resp) != "done" {
+		t.Fatalf("expected the handler result, got %q, %v", resp, err)
+	}
+}
@@ -1,27 +1,188 @@
 package tcp_server
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"google.golang.org/protobuf/proto"
 	"net"
 	"sync"
+	"time"
 )
 
+// SubscribeCommand — команда рукопожатия, которую клиент отправляет на порт уведомлений.
+const SubscribeCommand = "SUBSCRIBE"
+
+// handshakeTimeout — время, за которое клиент должен прислать токен сессии.
+const handshakeTimeout = 10 * time.Second
+
+// SubscribeRequest — полезная нагрузка команды SUBSCRIBE.
+type SubscribeRequest struct {
+	SessionToken string `json:"session_token"`
+}
+
+// Subscriber — получатель уведомлений: соединение на порту уведомлений или WebSocket.
+type Subscriber interface {
+	Send(resp *ServerResponse) error
+	Close() error
+	String() string
+}
+
+// handshakeMaxSize — предельный размер кадра рукопожатия.
+const handshakeMaxSize = 4 << 10
+
+// connSubscriber отправляет уведомления в TCP-соединение с заголовком длины.
+// Если события мультиплексируются с ответами в игровом соединении, запись сериализуется мьютексом.
+type connSubscriber struct {
+	conn         net.Conn
+	writeTimeout time.Duration
+	mu           sync.Mutex
+}
+
+func (c *connSubscriber) Send(resp *ServerResponse) error {
+	respBytes, err := proto.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	return c.write(respBytes)
+}
+
+func (c *connSubscriber) write(message []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Зависший клиент не должен навсегда занять горутину отправки его событий
+	if err := setDeadline(c.conn.SetWriteDeadline, c.writeTimeout); err != nil {
+		return err
+	}
+	return writeMessage(c.conn, message)
+}
+
+func (c *connSubscriber) Close() error {
+	return c.conn.Close()
+}
+
+func (c *connSubscriber) String() string {
+	return c.conn.RemoteAddr().String()
+}
+
+// subscriberQueueSize — сколько событий может ждать отправки одному получателю.
+// Получатель, отставший сильнее, отключается.
+const subscriberQueueSize = 64
+
+// subscription — очередь событий получателя. События отправляет отдельная горутина,
+// поэтому медленный получатель не задерживает рассылку остальным.
+type subscription struct {
+	session *Session
+	events  chan *ServerResponse
+	stop    chan struct{}
+	once    sync.Once
+}
+
+func (s *subscription) close() {
+	s.once.Do(func() { close(s.stop) })
+}
+
 // NotificationServer отвечает за управление уведомлениями.
 type NotificationServer struct {
-	address string
-	clients map[net.Conn]struct{}
-	mu      sync.Mutex
-	logger  ILogger
+	address     string
+	subscribers map[Subscriber]*subscription
+	sessions    *SessionManager
+	mu          sync.Mutex
+	logger      ILogger
+	debugEvents map[string]struct{} // Частые события, отправка которых пишется в журнал только на уровне Debug
 }
 
 // NewNotificationServer создаёт новый сервер уведомлений.
-func NewNotificationServer(address string, logger ILogger) *NotificationServer {
+func NewNotificationServer(address string, sessions *SessionManager, logger ILogger) *NotificationServer {
 	return &NotificationServer{
-		address: address,
-		clients: make(map[net.Conn]struct{}),
-		logger:  logger,
+		address:     address,
+		subscribers: make(map[Subscriber]*subscription),
+		sessions:    sessions,
+		logger:      logger,
+	}
+}
+
+// SetDebugEvents понижает до Debug запись в журнал об отправке перечисленных событий,
+// например ежесекундных.
+func (n *NotificationServer) SetDebugEvents(events ...string) {
+	debugEvents := make(map[string]struct{}, len(events))
+	for _, event := range events {
+		debugEvents[event] = struct{}{}
+	}
+	n.mu.Lock()
+	n.debugEvents = debugEvents
+	n.mu.Unlock()
+}
+
+// logSent пишет в журнал об отправке события
+func (n *NotificationServer) logSent(identity, event string) {
+	n.mu.Lock()
+	_, debug := n.debugEvents[event]
+	n.mu.Unlock()
+	message := fmt.Sprintf("Notification sent to %s: %s", identity, event)
+	if debug {
+		n.logger.Debug(message)
+		return
 	}
+	n.logger.Info(message)
+}
+
+// Subscribe подписывает получателя на уведомления игрока сессии.
+func (n *NotificationServer) Subscribe(session *Session, subscriber Subscriber) {
+	sub := &subscription{
+		session: session,
+		events:  make(chan *ServerResponse, subscriberQueueSize),
+		stop:    make(chan struct{}),
+	}
+	n.mu.Lock()
+	previous := n.subscribers[subscriber]
+	n.subscribers[subscriber] = sub
+	n.mu.Unlock()
+	if previous != nil {
+		previous.close()
+	}
+	go n.deliver(subscriber, sub)
+}
+
+// Unsubscribe отписывает получателя.
+func (n *NotificationServer) Unsubscribe(subscriber Subscriber) {
+	n.mu.Lock()
+	sub := n.subscribers[subscriber]
+	delete(n.subscribers, subscriber)
+	n.mu.Unlock()
+	if sub != nil {
+		sub.close()
+	}
+}
+
+// deliver отправляет события из очереди получателя, пока подписка не закрыта.
+// При ошибке записи получатель отключается.
+func (n *NotificationServer) deliver(subscriber Subscriber, sub *subscription) {
+	for {
+		select {
+		case <-sub.stop:
+			return
+		case resp := <-sub.events:
+			identity := sub.session.Identity()
+			if err := subscriber.Send(resp); err != nil {
+				n.logger.Error(fmt.Sprintf("Failed to send notification to %s: %v", identity, err))
+				n.drop(subscriber, sub)
+				return
+			}
+			n.logSent(identity, resp.Message)
+		}
+	}
+}
+
+// drop отписывает получателя и закрывает его соединение, если подписка ещё действует.
+func (n *NotificationServer) drop(subscriber Subscriber, sub *subscription) {
+	n.mu.Lock()
+	if n.subscribers[subscriber] == sub {
+		delete(n.subscribers, subscriber)
+	}
+	n.mu.Unlock()
+	sub.close()
+	subscriber.Close()
 }
 
 // Start запускает сервер уведомлений.
@@ -41,26 +202,29 @@ func (n *NotificationServer) Start() error {
 			continue
 		}
 
-		n.mu.Lock()
-		n.clients[conn] = struct{}{}
-		n.mu.Unlock()
-
 		n.logger.Info(fmt.Sprintf("New notification client connected: %s", conn.RemoteAddr().String()))
 
 		go n.handleConnection(conn)
 	}
 }
 
-// handleConnection обрабатывает отключение клиента.
+// handleConnection выполняет рукопожатие и обрабатывает отключение клиента.
 func (n *NotificationServer) handleConnection(conn net.Conn) {
+	subscriber := &connSubscriber{conn: conn, writeTimeout: DefaultWriteTimeout}
 	defer func() {
 		conn.Close()
-		n.mu.Lock()
-		delete(n.clients, conn)
-		n.mu.Unlock()
+		n.Unsubscribe(subscriber)
 		n.logger.Info(fmt.Sprintf("Notification client disconnected: %s", conn.RemoteAddr().String()))
 	}()
 
+	session, err := n.handshake(conn)
+	if err != nil {
+		n.logger.Error(fmt.Sprintf("Handshake with %s failed: %v", conn.RemoteAddr().String(), err))
+		return
+	}
+
+	n.Subscribe(session, subscriber)
+
 	// Чтение здесь для того, чтобы конекшн просто не падал
 	buffer := make([]byte, 1024)
 	for {
@@ -75,48 +239,108 @@ func (n *NotificationServer) handleConnection(conn net.Conn) {
 	}
 }
 
-// Notify отправляет уведомление только указанным клиентам по IP.
-func (n *NotificationServer) Notify(event string, payload []byte, clientIPs []string) {
+// handshake читает команду SUBSCRIBE и находит сессию по переданному токену.
+func (n *NotificationServer) handshake(conn net.Conn) (*Session, error) {
+	if err := conn.SetReadDeadline(time.Now().Add(handshakeTimeout)); err != nil {
+		return nil, err
+	}
+	message, err := readMessage(conn, handshakeMaxSize)
+	if err != nil {
+		if errors.Is(err, ErrFrameTooLarge) {
+			writeMessage(conn, CreateErrorResponse(StatusPayloadTooLarge, "Handshake message is too large"))
+		}
+		return nil, err
+	}
+	if err := conn.SetReadDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+
+	var clientMsg ClientMessage
+	if err := proto.Unmarshal(message, &clientMsg); err != nil {
+		writeMessage(conn, CreateErrorResponse(StatusBadRequest, "Invalid Protobuf format"))
+		return nil, err
+	}
+	if clientMsg.Command != SubscribeCommand {
+		writeMessage(conn, CreateErrorResponse(StatusBadRequest, "Expected SUBSCRIBE command"))
+		return nil, fmt.Errorf("unexpected command: %s", clientMsg.Command)
+	}
+
+	var req SubscribeRequest
+	if err := json.Unmarshal(clientMsg.Payload, &req); err != nil {
+		writeMessage(conn, CreateErrorResponse(StatusBadRequest, "Invalid SUBSCRIBE payload"))
+		return nil, err
+	}
+	session, ok := n.sessions.Resolve(req.SessionToken)
+	if !ok {
+		writeMessage(conn, CreateErrorResponse(StatusUnauthorized, "Invalid session token"))
+		return nil, fmt.Errorf("unknown session token")
+	}
+
+	respBytes, err := proto.Marshal(&ServerResponse{
+		StatusCode: StatusSuccess,
+		Message:    "Subscribed",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := writeMessage(conn, respBytes); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Disconnect закрывает все подписки, привязанные к сессии.
+func (n *NotificationServer) Disconnect(session *Session) {
 	n.mu.Lock()
-	defer n.mu.Unlock()
+	closed := make(map[Subscriber]*subscription)
+	for subscriber, sub := range n.subscribers {
+		if sub.session == session {
+			delete(n.subscribers, subscriber)
+			closed[subscriber] = sub
+		}
+	}
+	n.mu.Unlock()
 
+	for subscriber, sub := range closed {
+		sub.close()
+		subscriber.Close()
+	}
+}
+
+// Notify ставит уведомление в очереди подписок указанных игроков и не ждёт отправки.
+func (n *NotificationServer) Notify(event string, payload []byte, identities []string) {
 	serverResp := &ServerResponse{
 		StatusCode: StatusSuccess,
 		Message:    event,
 		Payload:    payload,
+		Kind:       MessageKind_EVENT,
 	}
 
-	respBytes, err := proto.Marshal(serverResp)
-	if err != nil {
-		n.logger.Error(fmt.Sprintf("Failed to serialize notification: %v", err))
-		return
-	}
-
-	// Создаём множество IP-адресов для быстрого поиска
-	targetIPs := make(map[string]struct{}, len(clientIPs))
-	for _, ip := range clientIPs {
-		targetIPs[ip] = struct{}{}
+	// Создаём множество игроков для быстрого поиска
+	targets := make(map[string]struct{}, len(identities))
+	for _, identity := range identities {
+		targets[identity] = struct{}{}
 	}
 
-	// Отправляем только клиентам с указанными IP
-	for conn := range n.clients {
-		clientIP, _, err := net.SplitHostPort(conn.RemoteAddr().String())
-		if err != nil {
-			n.logger.Error(fmt.Sprintf("Failed to parse client address: %v", err))
+	// Ставим в очередь только подпискам сессий указанных игроков
+	lagging := make(map[Subscriber]*subscription)
+	n.mu.Lock()
+	for subscriber, sub := range n.subscribers {
+		identity := sub.session.Identity()
+		if _, exists := targets[identity]; !exists || identity == "" {
+			n.logger.Debug(fmt.Sprintf("Skipping notification for %s", subscriber.String()))
 			continue
 		}
-
-		// Проверяем, есть ли клиентский IP в списке
-		if _, exists := targetIPs[clientIP]; exists {
-			if err := writeMessage(conn, respBytes); err != nil {
-				n.logger.Error(fmt.Sprintf("Failed to send notification to %s: %v", conn.RemoteAddr().String(), err))
-				delete(n.clients, conn)
-				conn.Close()
-			} else {
-				n.logger.Info(fmt.Sprintf("Notification sent to %s: %s", conn.RemoteAddr().String(), serverResp.Message))
-			}
-		} else {
-			n.logger.Debug(fmt.Sprintf("Skipping notification for %s", conn.RemoteAddr().String()))
+		select {
+		case sub.events <- serverResp:
+		default:
+			lagging[subscriber] = sub
 		}
 	}
+	n.mu.Unlock()
+
+	for subscriber, sub := range lagging {
+		n.logger.Warning(fmt.Sprintf("Notification queue of %s is full, disconnecting", sub.session.Identity()))
+		n.drop(subscriber, sub)
+	}
 }
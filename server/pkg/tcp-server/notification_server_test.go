@@ -0,0 +1,110 @@
+package tcp_server
+
+import (
+	"testing"
+	"time"
+)
+
+// chanSubscriber передаёт события в канал; пока release не закрыт, отправка висит
+type chanSubscriber struct {
+	events  chan string
+	release chan struct{}
+	closed  chan struct{}
+}
+
+func newChanSubscriber(blocked bool) *chanSubscriber {
+	s := &chanSubscriber{events: make(chan string, subscriberQueueSize*2), release: make(chan struct{}), closed: make(chan struct{})}
+	if !blocked {
+		close(s.release)
+	}
+	return s
+}
+
+func (s *chanSubscriber) Send(resp *ServerResponse) error {
+	<-s.release
+	s.events <- resp.Message
+	return nil
+}
+
+func (s *chanSubscriber) Close() error {
+	close(s.closed)
+	return nil
+}
+
+func (s *chanSubscriber) String() string { return "chan" }
+
+func TestNotifyDoesNotWaitForSlowSubscribers(t *testing.T) {
+	sessions := NewSessionManager()
+	n := NewNotificationServer("", sessions, nopLogger{})
+	subscribe := func(identity string, subscriber Subscriber) {
+		session, _ := sessions.Issue()
+		session.Bind(identity)
+		n.Subscribe(session, subscriber)
+	}
+	stalled, fast := newChanSubscriber(true), newChanSubscriber(false)
+	subscribe("alice", stalled)
+	subscribe("bob", fast)
+
+	start := time.Now()
+	n.Notify("GuessMade", nil, []string{"alice", "bob"})
+	select {
+	case event := <-fast.events:
+		if event != "GuessMade" {
+			t.Fatalf("unexpected event %q", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("a stalled subscriber must not delay others")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Notify took %v", elapsed)
+	}
+	// Subscribe и Unsubscribe тоже не ждут зависшую отправку
+	n.Unsubscribe(fast)
+
+	// Получатель, чья очередь переполнилась, отключается
+	for i := 0; i < subscriberQueueSize+1; i++ {
+		n.Notify("TimerTick", nil, []string{"alice"})
+	}
+	select {
+	case <-stalled.closed:
+	case <-time.After(time.Second):
+		t.Fatal("lagging subscriber must be disconnected")
+	}
+	close(stalled.release)
+}
+
+// levelLogger запоминает уровни, на которых записаны сообщения об отправке
+type levelLogger struct {
+	nopLogger
+	levels chan string
+}
+
+func (l levelLogger) Info(msg string)  { l.levels <- "info: " + msg }
+func (l levelLogger) Debug(msg string) { l.levels <- "debug: " + msg }
+
+func TestDebugEventsAreLoggedAtDebug(t *testing.T) {
+	sessions := NewSessionManager()
+	logger := levelLogger{levels: make(chan string, 4)}
+	n := NewNotificationServer("", sessions, logger)
+	n.SetDebugEvents("TimerTick")
+	session, _ := sessions.Issue()
+	session.Bind("alice")
+	subscriber := newChanSubscriber(false)
+	n.Subscribe(session, subscriber)
+
+	for _, event := range []string{"TimerTick", "GameStarted"} {
+		n.Notify(event, nil, []string{"alice"})
+		<-subscriber.events
+	}
+	want := []string{"debug: Notification sent to alice: TimerTick", "info: Notification sent to alice: GameStarted"}
+	for _, expected := range want {
+		select {
+		case got := <-logger.levels:
+			if got != expected {
+				t.Errorf("expected %q, got %q", expected, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("missing log entry %q", expected)
+		}
+	}
+}
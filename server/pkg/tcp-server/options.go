@@ -0,0 +1,43 @@
+package tcp_server
+
+import "time"
+
+// Значения по умолчанию для ограничений соединения.
+const (
+	DefaultMaxFrameSize = 1 << 20 // 1 МБ
+	DefaultReadTimeout  = 10 * time.Second
+	DefaultWriteTimeout = 10 * time.Second
+	DefaultIdleTimeout  = 10 * time.Minute
+)
+
+// Option настраивает сервер.
+type Option func(*Server)
+
+// WithMaxFrameSize задаёт максимальный размер тела кадра в байтах.
+func WithMaxFrameSize(size int) Option {
+	return func(s *Server) {
+		s.maxFrameSize = size
+	}
+}
+
+// WithReadTimeout задаёт время на чтение тела кадра после получения заголовка.
+func WithReadTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.readTimeout = timeout
+	}
+}
+
+// WithWriteTimeout задаёт время на отправку одного кадра.
+func WithWriteTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.writeTimeout = timeout
+	}
+}
+
+// WithIdleTimeout задаёт, сколько соединение может простаивать между запросами.
+// Нулевое значение отключает ограничение.
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.idleTimeout = timeout
+	}
+}
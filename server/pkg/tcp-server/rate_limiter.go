@@ -0,0 +1,142 @@
+package tcp_server
+
+import (
+	"context"
+	"fmt"
+	"hangman/internal/errs"
+	"sync"
+	"time"
+)
+
+// RateLimit — параметры корзины токенов: скорость пополнения и ёмкость.
+// Нулевая скорость отключает ограничение.
+type RateLimit struct {
+	Rate  float64 // Токенов в секунду
+	Burst int     // Сколько запросов можно отправить подряд
+}
+
+// RateLimitConfig задаёт лимиты для соединения, игрока и отдельных команд.
+type RateLimitConfig struct {
+	PerConnection RateLimit            // На соединение (сессию)
+	PerPlayer     RateLimit            // На игрока во всех его соединениях
+	PerCommand    map[string]RateLimit // На команду для игрока, а до входа — для соединения
+}
+
+// bucketTTL — через сколько простоя корзина удаляется из памяти.
+const bucketTTL = 10 * time.Minute
+
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// RateLimiter хранит корзины токенов по ключам ограничений.
+type RateLimiter struct {
+	config    RateLimitConfig
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+// NewRateLimiter создаёт ограничитель с заданной конфигурацией.
+func NewRateLimiter(config RateLimitConfig) *RateLimiter {
+	return &RateLimiter{
+		config:  config,
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// take забирает токен из корзины ключа, если он есть.
+func (l *RateLimiter) take(key string, limit RateLimit, now time.Time) bool {
+	if limit.Rate <= 0 {
+		return true
+	}
+
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: burst, lastSeen: now}
+		l.buckets[key] = bucket
+	}
+
+	// Пополняем корзину за прошедшее время
+	bucket.tokens += now.Sub(bucket.lastSeen).Seconds() * limit.Rate
+	if bucket.tokens > burst {
+		bucket.tokens = burst
+	}
+	bucket.lastSeen = now
+
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// sweep удаляет давно не использованные корзины.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < bucketTTL {
+		return
+	}
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.lastSeen) > bucketTTL {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// Allow проверяет все лимиты, применимые к команде.
+// Возвращает название нарушенного лимита или пустую строку.
+func (l *RateLimiter) Allow(connection, player, command string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	if !l.take("conn:"+connection, l.config.PerConnection, now) {
+		return "connection"
+	}
+	owner := "conn:" + connection
+	if player != "" {
+		if !l.take("player:"+player, l.config.PerPlayer, now) {
+			return "player"
+		}
+		owner = "player:" + player
+	}
+	if limit, ok := l.config.PerCommand[command]; ok {
+		if !l.take(owner+"/"+command, limit, now) {
+			return "command"
+		}
+	}
+	return ""
+}
+
+// RateLimiting отклоняет команды сверх лимитов с кодом 4029 и записывает нарушителей в журнал.
+func RateLimiting(limiter *RateLimiter, logger ILogger) Middleware {
+	return func(next HandleFunc) HandleFunc {
+		return func(ctx context.Context, message []byte) ([]byte, error) {
+			session, ok := GetSession(ctx)
+			if !ok {
+				return next(ctx, message)
+			}
+			command, _ := GetCommand(ctx)
+
+			if scope := limiter.Allow(session.Token(), session.Identity(), command); scope != "" {
+				who := session.Identity()
+				if conn, ok := GetConn(ctx); ok && who == "" {
+					who = conn.RemoteAddr().String()
+				}
+				logger.Warning(fmt.Sprintf("Rate limit (%s) exceeded by %s on %s", scope, who, command))
+				return nil, errs.NewError(StatusTooManyRequests, "Too many requests, slow down")
+			}
+			return next(ctx, message)
+		}
+	}
+}
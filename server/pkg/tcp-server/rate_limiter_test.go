@@ -0,0 +1,53 @@
+package tcp_server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBuckets(t *testing.T) {
+	limiter := NewRateLimiter(RateLimitConfig{
+		PerConnection: RateLimit{Rate: 100, Burst: 100},
+		PerCommand:    map[string]RateLimit{"CREATE_ROOM": {Rate: 1, Burst: 2}},
+	})
+	now := time.Unix(0, 0)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if scope := limiter.Allow("conn1", "alice", "CREATE_ROOM"); scope != "" {
+			t.Fatalf("request %d rejected by %s limit", i, scope)
+		}
+	}
+	if scope := limiter.Allow("conn1", "alice", "CREATE_ROOM"); scope != "command" {
+		t.Fatalf("expected command limit, got %q", scope)
+	}
+
+	// Лимит команды считается на игрока, а не на соединение
+	if scope := limiter.Allow("conn2", "alice", "CREATE_ROOM"); scope != "command" {
+		t.Fatalf("expected command limit on second connection, got %q", scope)
+	}
+	if scope := limiter.Allow("conn1", "alice", "GUESS_LETTER"); scope != "" {
+		t.Fatalf("other commands must not be limited, got %q", scope)
+	}
+
+	// За секунду корзина пополняется на один токен
+	now = now.Add(time.Second)
+	if scope := limiter.Allow("conn1", "alice", "CREATE_ROOM"); scope != "" {
+		t.Fatalf("expected refill, got %q", scope)
+	}
+}
+
+func TestRateLimiterPerConnection(t *testing.T) {
+	limiter := NewRateLimiter(RateLimitConfig{PerConnection: RateLimit{Rate: 1, Burst: 1}})
+	limiter.now = func() time.Time { return time.Unix(0, 0) }
+
+	if scope := limiter.Allow("conn1", "", "PING"); scope != "" {
+		t.Fatalf("first request rejected by %s limit", scope)
+	}
+	if scope := limiter.Allow("conn1", "", "PING"); scope != "connection" {
+		t.Fatalf("expected connection limit, got %q", scope)
+	}
+	if scope := limiter.Allow("conn2", "", "PING"); scope != "" {
+		t.Fatalf("other connection rejected by %s limit", scope)
+	}
+}
@@ -2,13 +2,14 @@ package tcp_server
 
 import (
 	"context"
-	"encoding/binary"
 	"errors"
 	"fmt"
 	"google.golang.org/protobuf/proto"
 	"hangman/internal/errs"
 	ctx_repo "hangman/pkg/ctx-repo"
+	"io"
 	"net"
+	"time"
 )
 
 type ILogger interface {
@@ -22,28 +23,50 @@ type ILogger interface {
 type Server struct {
 	address            string
 	handlers           map[string]HandleFunc
+	middlewares        []Middleware
 	notificationServer *NotificationServer
+	sessions           *SessionManager
 	ctxRepo            *ctx_repo.CtxRepository
 
+	maxFrameSize int
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+	idleTimeout  time.Duration
+
 	logger ILogger
 }
 
 // New создает новый сервер
-func New(address string, ctxRepo *ctx_repo.CtxRepository, logger ILogger) *Server {
-	notificationSrv := NewNotificationServer(":8002", logger)
+func New(address string, ctxRepo *ctx_repo.CtxRepository, logger ILogger, opts ...Option) *Server {
+	sessions := NewSessionManager()
+	notificationSrv := NewNotificationServer(":8002", sessions, logger)
 	go func() {
 		if err := notificationSrv.Start(); err != nil {
 			logger.Fatal(fmt.Sprintf("Failed to start notification server: %v", err))
 		}
 	}()
 
-	return &Server{
+	s := &Server{
 		address:            address,
 		handlers:           make(map[string]HandleFunc),
 		notificationServer: notificationSrv,
+		sessions:           sessions,
 		logger:             logger,
 		ctxRepo:            ctxRepo,
+		maxFrameSize:       DefaultMaxFrameSize,
+		readTimeout:        DefaultReadTimeout,
+		writeTimeout:       DefaultWriteTimeout,
+		idleTimeout:        DefaultIdleTimeout,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
+}
+
+// NotificationServer возвращает сервер уведомлений.
+func (s *Server) NotificationServer() *NotificationServer {
+	return s.notificationServer
 }
 
 // RegisterHandler registers a handler for a specific command.
@@ -80,111 +103,149 @@ func (s *Server) handleConnection(conn net.Conn) {
 		s.logger.Info(fmt.Sprintf("Game client disconnected: %s", conn.RemoteAddr().String()))
 	}()
 	clientAddr := conn.RemoteAddr().String()
+	subscriber := &connSubscriber{conn: conn, writeTimeout: s.writeTimeout}
 	s.logger.Info(fmt.Sprintf("New connection from %s", clientAddr))
 
+	// Выдаём соединению сессию, по которой к нему привязываются уведомления
+	session, err := s.sessions.Issue()
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to issue session for %s: %v", clientAddr, err))
+		return
+	}
+	defer func() {
+		s.sessions.Revoke(session.Token())
+		s.notificationServer.Disconnect(session)
+	}()
+
 	// Создаём контекст
 	ctx := context.Background()
-	// Пробрасываем соединение, логгер и функцию отмены в контекст
+	// Пробрасываем соединение, логгер, сессию и функцию отмены в контекст
 	ctx = SetConn(ctx, conn)
 	ctx = SetLogger(ctx, s.logger)
 	ctx = SetNotificationServer(ctx, s.notificationServer)
+	ctx = SetSession(ctx, session)
 	s.ctxRepo.UpdateOrInsertCtx(clientAddr, ctx)
 	defer s.ctxRepo.CancelContext(clientAddr)
+	// Обработчики получают отменяемый контекст: при отключении клиента
+	// отменяются и все производные от него контексты (например, участие в комнатах)
+	if connCtx, ok := s.ctxRepo.GetContext(clientAddr); ok {
+		ctx = *connCtx
+	}
 
 	for {
-		var response []byte
-		message, err := readMessage(conn)
+		message, err := s.readFrame(conn)
 		if err != nil {
-			s.logger.Error(fmt.Sprintf("Failed to read message: %v", err))
-			s.ctxRepo.CancelContext(clientAddr)
-			response = CreateErrorResponse(StatusInternalServerError, err.Error())
-			break
-		} else {
-			response = s.processMessage(ctx, message)
+			s.handleReadError(clientAddr, subscriber, err)
+			return
+		}
+		if err := subscriber.write(s.processMessage(ctx, subscriber, message)); err != nil {
+			s.logger.Error(fmt.Sprintf("Failed to write response to %s: %v", clientAddr, err))
+			return
 		}
-		writeMessage(conn, response)
 	}
 }
 
-func readMessage(conn net.Conn) ([]byte, error) {
-	// Чтение заголовка (4 байта)
-	header := make([]byte, 4)
-	if _, err := conn.Read(header); err != nil {
+// readFrame ждёт следующий кадр не дольше idleTimeout,
+// а после получения заголовка даёт readTimeout на чтение остального.
+func (s *Server) readFrame(conn net.Conn) ([]byte, error) {
+	if err := setDeadline(conn.SetReadDeadline, s.idleTimeout); err != nil {
 		return nil, err
 	}
-
-	// Преобразование заголовка в длину сообщения
-	messageLength := int(binary.BigEndian.Uint32(header))
-
-	// Чтение тела сообщения
-	message := make([]byte, messageLength)
-	if _, err := conn.Read(message); err != nil {
+	length, err := readHeader(conn, s.maxFrameSize)
+	if err != nil {
 		return nil, err
 	}
-	return message, nil
-}
-
-// writeMessage отправляет сообщение с заголовком длины
-func writeMessage(conn net.Conn, message []byte) error {
-	header := make([]byte, 4)
-	binary.BigEndian.PutUint32(header, uint32(len(message)))
-
-	if _, err := conn.Write(header); err != nil {
-		return err
+	if err := setDeadline(conn.SetReadDeadline, s.readTimeout); err != nil {
+		return nil, err
 	}
+	return readBody(conn, length)
+}
 
-	if _, err := conn.Write(message); err != nil {
-		return err
+// handleReadError сообщает клиенту причину закрытия соединения, если её можно объяснить.
+func (s *Server) handleReadError(clientAddr string, subscriber *connSubscriber, err error) {
+	switch {
+	case errors.Is(err, io.EOF):
+		return
+	case errors.Is(err, ErrFrameTooLarge):
+		s.logger.Warning(fmt.Sprintf("Rejected frame from %s: %v", clientAddr, err))
+		subscriber.write(CreateErrorResponse(StatusPayloadTooLarge, fmt.Sprintf("Message exceeds %d bytes", s.maxFrameSize)))
+	case isTimeout(err):
+		s.logger.Warning(fmt.Sprintf("Connection %s timed out: %v", clientAddr, err))
+		subscriber.write(CreateErrorResponse(StatusRequestTimeout, "Connection timed out"))
+	default:
+		s.logger.Error(fmt.Sprintf("Failed to read message from %s: %v", clientAddr, err))
 	}
-
-	return nil
 }
 
-func (s *Server) processMessage(ctx context.Context, message []byte) []byte {
+func (s *Server) processMessage(ctx context.Context, subscriber *connSubscriber, message []byte) []byte {
 	// Парсим сообщение клиента
 	var clientMsg ClientMessage
 	if err := proto.Unmarshal(message, &clientMsg); err != nil {
 		s.logger.Error(fmt.Sprintf("Failed to parse Protobuf message: %v", err))
 		return CreateErrorResponse(StatusBadRequest, "Invalid Protobuf format")
 	}
-	s.logger.Info(fmt.Sprintf("Request: Command: %s, PayloadВize: %d bytes", clientMsg.Command, len(clientMsg.Payload)))
-	s.logger.Debug(fmt.Sprintf("Request: Command: %s, Payload: %s", clientMsg.Command, string(clientMsg.Payload)))
 
-	// Ищем обработчик для команды
-	handler, exists := s.handlers[clientMsg.Command]
+	var serverResp *ServerResponse
+	if clientMsg.Command == SubscribeCommand {
+		// Клиент переходит в режим одного соединения: события приходят вместе с ответами.
+		// Подписка проходит через те же middleware, что и остальные команды
+		serverResp = s.run(ctx, clientMsg.Command, s.subscribe(subscriber), clientMsg.Payload)
+	} else {
+		serverResp = s.dispatch(ctx, clientMsg.Command, clientMsg.Payload)
+	}
+	serverResp.RequestId = clientMsg.RequestId
+
+	// Сериализация ответа
+	respBytes, err := proto.Marshal(serverResp)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to serialize response: %v", err))
+		return CreateErrorResponse(StatusInternalServerError, "Internal server error")
+	}
+	return respBytes
+}
+
+// subscribe возвращает обработчик, подписывающий игровое соединение на события его сессии.
+func (s *Server) subscribe(subscriber *connSubscriber) HandleFunc {
+	return func(ctx context.Context, _ []byte) ([]byte, error) {
+		session, ok := GetSession(ctx)
+		if !ok {
+			return nil, errs.NewError(StatusInternalServerError, "Session not found")
+		}
+		s.notificationServer.Subscribe(session, subscriber)
+		return nil, nil
+	}
+}
+
+// dispatch находит обработчик команды, вызывает его через цепочку middleware и формирует ответ.
+// Используется всеми транспортами: TCP и WebSocket.
+func (s *Server) dispatch(ctx context.Context, command string, payload []byte) *ServerResponse {
+	handler, exists := s.handlers[command]
 	if !exists {
-		s.logger.Error(fmt.Sprintf("Unknown command: %s", clientMsg.Command))
-		return CreateErrorResponse(StatusNotFound, "Unknown command")
+		handler = unknownCommand
 	}
+	return s.run(ctx, command, handler, payload)
+}
 
-	// Обрабатываем полезную нагрузку
-	responsePayload, err := handler(ctx, clientMsg.Payload)
+// run вызывает обработчик через цепочку middleware и формирует ответ.
+func (s *Server) run(ctx context.Context, command string, handler HandleFunc, payload []byte) *ServerResponse {
+	ctx = SetCommand(ctx, command)
+	responsePayload, err := s.chain(handler)(ctx, payload)
 	if err != nil {
 		var customErr *errs.Error
 		if errors.As(err, &customErr) {
-			s.logger.Error(fmt.Sprintf("Error in handler: %v", customErr))
-			return CreateErrorResponse(customErr.Code, customErr.Message)
+			return &ServerResponse{StatusCode: customErr.Code, Message: customErr.Message}
 		}
 
 		// Если ошибка неизвестного типа, возвращаем стандартный код
-		s.logger.Error(fmt.Sprintf("Unexpected error: %v", err))
-		return CreateErrorResponse(StatusInternalServerError, "Internal server error")
+		s.logger.Error(fmt.Sprintf("Unexpected error in %s: %v", command, err))
+		return &ServerResponse{StatusCode: StatusInternalServerError, Message: "Internal server error"}
 	}
-	// Создаем ответ
-	serverResp := &ServerResponse{
+
+	return &ServerResponse{
 		StatusCode: StatusSuccess,
 		Message:    "Success",
 		Payload:    responsePayload,
 	}
-
-	// Сериализация ответа
-	respBytes, err := proto.Marshal(serverResp)
-	if err != nil {
-		s.logger.Error(fmt.Sprintf("Failed to serialize response: %v", err))
-		return CreateErrorResponse(StatusInternalServerError, "Internal server error")
-	}
-	s.logger.Debug(fmt.Sprintf("Response: %s, Payload: %s", serverResp.Message, responsePayload))
-	return respBytes
 }
 
 // CreateErrorResponse формирует Protobuf-ответ с ошибкой
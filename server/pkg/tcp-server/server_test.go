@@ -0,0 +1,223 @@
+package tcp_server
+
+import (
+	"context"
+	"encoding/json"
+	"hangman/internal/errs"
+	ctx_repo "hangman/pkg/ctx-repo"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// testLogin — тело запроса и ответа LOGIN тестового сервера
+type testLogin struct {
+	Username     string `json:"username,omitempty"`
+	SessionToken string `json:"session_token,omitempty"`
+}
+
+// newTestServer запускает сервер на свободном порту. LOGIN привязывает сессию
+// к имени из запроса и возвращает токен сессии.
+func newTestServer(t *testing.T, middlewares ...Middleware) (*Server, string) {
+	t.Helper()
+	sessions := NewSessionManager()
+	s := &Server{
+		handlers:           make(map[string]HandleFunc),
+		notificationServer: NewNotificationServer("", sessions, nopLogger{}),
+		sessions:           sessions,
+		ctxRepo:            ctx_repo.NewCtxRepository(),
+		maxFrameSize:       DefaultMaxFrameSize,
+		readTimeout:        DefaultReadTimeout,
+		writeTimeout:       DefaultWriteTimeout,
+		idleTimeout:        DefaultIdleTimeout,
+		logger:             nopLogger{},
+	}
+	s.Use(middlewares...)
+	s.RegisterHandler("LOGIN", func(ctx context.Context, payload []byte) ([]byte, error) {
+		var req testLogin
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, err
+		}
+		session, _ := GetSession(ctx)
+		session.Bind(req.Username)
+		return json.Marshal(testLogin{SessionToken: session.Token()})
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.handleConnection(conn)
+		}
+	}()
+	return s, listener.Addr().String()
+}
+
+func dialTest(t *testing.T, address string) net.Conn {
+	t.Helper()
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func sendCommand(t *testing.T, conn net.Conn, requestID, command string, payload []byte) {
+	t.Helper()
+	message, _ := proto.Marshal(&ClientMessage{Command: command, Payload: payload, RequestId: requestID})
+	if err := writeMessage(conn, message); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readResponse(t *testing.T, conn net.Conn) *ServerResponse {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	message, err := readMessage(conn, DefaultMaxFrameSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp ServerResponse
+	if err := proto.Unmarshal(message, &resp); err != nil {
+		t.Fatal(err)
+	}
+	return &resp
+}
+
+// login входит под именем username и возвращает токен сессии соединения
+func login(t *testing.T, conn net.Conn, username string) string {
+	t.Helper()
+	payload, _ := json.Marshal(testLogin{Username: username})
+	sendCommand(t, conn, "", "LOGIN", payload)
+	var resp testLogin
+	if err := json.Unmarshal(readResponse(t, conn).Payload, &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.SessionToken
+}
+
+func TestSubscribeOverGameConnection(t *testing.T) {
+	s, address := newTestServer(t)
+	conn := dialTest(t, address)
+
+	token := login(t, conn, "alice")
+	if session, ok := s.sessions.Resolve(token); !ok || session.Identity() != "alice" {
+		t.Fatalf("session token %q must resolve to alice", token)
+	}
+
+	sendCommand(t, conn, "2", SubscribeCommand, nil)
+	if resp := readResponse(t, conn); resp.StatusCode != StatusSuccess || resp.RequestId != "2" {
+		t.Fatalf("unexpected SUBSCRIBE response: %v", resp)
+	}
+	s.notificationServer.Notify("GameStarted", []byte(`{}`), []string{"alice"})
+	if event := readResponse(t, conn); event.Kind != MessageKind_EVENT || event.Message != "GameStarted" {
+		t.Fatalf("expected the event on the game connection, got %v", event)
+	}
+
+	// После отключения токен больше не действует
+	conn.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, ok := s.sessions.Resolve(token); !ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("session token must be revoked after disconnect")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNotificationHandshake(t *testing.T) {
+	s, address := newTestServer(t)
+	conn := dialTest(t, address)
+	token := login(t, conn, "alice")
+
+	tests := []struct {
+		name    string
+		command string
+		payload string
+		status  int32
+	}{
+		{name: "valid token", command: SubscribeCommand, payload: `{"session_token":"` + token + `"}`, status: StatusSuccess},
+		{name: "unknown token", command: SubscribeCommand, payload: `{"session_token":"deadbeef"}`, status: StatusUnauthorized},
+		{name: "invalid payload", command: SubscribeCommand, payload: `{`, status: StatusBadRequest},
+		{name: "wrong command", command: "LOGIN", payload: `{}`, status: StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := net.Pipe()
+			defer client.Close()
+			go s.notificationServer.handleConnection(server)
+
+			sendCommand(t, client, "", tt.command, []byte(tt.payload))
+			if resp := readResponse(t, client); resp.StatusCode != tt.status {
+				t.Fatalf("expected %d, got %v", tt.status, resp)
+			}
+		})
+	}
+}
+
+func TestPipelinedRequestsKeepRequestID(t *testing.T) {
+	_, address := newTestServer(t)
+	conn := dialTest(t, address)
+
+	// Клиент отправляет запросы подряд, не дожидаясь ответов
+	requests := []struct {
+		id      string
+		command string
+		payload string
+		status  int32
+	}{
+		{id: "a1", command: "LOGIN", payload: `{"username":"alice"}`, status: StatusSuccess},
+		{id: "a2", command: "UNKNOWN", status: StatusNotFound},
+		{id: "", command: "LOGIN", payload: `{"username":"alice"}`, status: StatusSuccess},
+		{id: "a4", command: "LOGIN", payload: `{`, status: StatusInternalServerError},
+		{id: "a5", command: SubscribeCommand, status: StatusSuccess},
+	}
+	for _, req := range requests {
+		sendCommand(t, conn, req.id, req.command, []byte(req.payload))
+	}
+	for _, req := range requests {
+		resp := readResponse(t, conn)
+		if resp.RequestId != req.id || resp.StatusCode != req.status || resp.Kind != MessageKind_RESPONSE {
+			t.Fatalf("expected response %q with %d, got %v", req.id, req.status, resp)
+		}
+	}
+}
+
+func TestSubscribeGoesThroughMiddleware(t *testing.T) {
+	// Ограничение частоты отклоняет подписку так же, как любую другую команду
+	limited := func(next HandleFunc) HandleFunc {
+		return func(ctx context.Context, message []byte) ([]byte, error) {
+			if command, _ := GetCommand(ctx); command == SubscribeCommand {
+				return nil, errs.NewError(StatusTooManyRequests, "Too many requests")
+			}
+			return next(ctx, message)
+		}
+	}
+	s, address := newTestServer(t, limited)
+	conn := dialTest(t, address)
+	login(t, conn, "alice")
+
+	sendCommand(t, conn, "s1", SubscribeCommand, nil)
+	if resp := readResponse(t, conn); resp.StatusCode != StatusTooManyRequests || resp.RequestId != "s1" {
+		t.Fatalf("expected SUBSCRIBE to be rejected by middleware, got %v", resp)
+	}
+	s.notificationServer.mu.Lock()
+	subscribers := len(s.notificationServer.subscribers)
+	s.notificationServer.mu.Unlock()
+	if subscribers != 0 {
+		t.Fatalf("rejected SUBSCRIBE must not subscribe the connection")
+	}
+}
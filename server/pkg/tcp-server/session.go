@@ -0,0 +1,76 @@
+package tcp_server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+)
+
+// Session описывает сессию игрового соединения.
+// Токен выдаётся при подключении, а личность игрока привязывается позже.
+type Session struct {
+	token    string
+	identity string
+	mu       sync.RWMutex
+}
+
+// Token возвращает токен сессии.
+func (s *Session) Token() string {
+	return s.token
+}
+
+// Identity возвращает имя игрока, привязанного к сессии.
+func (s *Session) Identity() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.identity
+}
+
+// Bind привязывает сессию к игроку.
+func (s *Session) Bind(identity string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.identity = identity
+}
+
+// SessionManager выдаёт токены сессий и находит сессию по токену.
+type SessionManager struct {
+	sessions map[string]*Session
+	mu       sync.RWMutex
+}
+
+// NewSessionManager создаёт новый менеджер сессий.
+func NewSessionManager() *SessionManager {
+	return &SessionManager{
+		sessions: make(map[string]*Session),
+	}
+}
+
+// Issue создаёт новую сессию со случайным токеном.
+func (m *SessionManager) Issue() (*Session, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	session := &Session{token: hex.EncodeToString(buf)}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[session.token] = session
+	return session, nil
+}
+
+// Resolve возвращает сессию по токену.
+func (m *SessionManager) Resolve(token string) (*Session, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	session, ok := m.sessions[token]
+	return session, ok
+}
+
+// Revoke удаляет сессию, после чего токен становится недействительным.
+func (m *SessionManager) Revoke(token string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, token)
+}
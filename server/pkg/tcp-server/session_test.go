@@ -0,0 +1,40 @@
+package tcp_server
+
+import "testing"
+
+func TestSessionManager(t *testing.T) {
+	sessions := NewSessionManager()
+	alice, err := sessions.Issue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, _ := sessions.Issue()
+	if alice.Token() == bob.Token() || len(alice.Token()) != 32 {
+		t.Fatalf("tokens must be unique 16-byte hex strings, got %q and %q", alice.Token(), bob.Token())
+	}
+	alice.Bind("alice")
+	sessions.Revoke(bob.Token())
+
+	tests := []struct {
+		name     string
+		token    string
+		found    bool
+		identity string
+	}{
+		{name: "issued and bound", token: alice.Token(), found: true, identity: "alice"},
+		{name: "revoked on disconnect", token: bob.Token()},
+		{name: "unknown", token: "deadbeef"},
+		{name: "empty", token: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, ok := sessions.Resolve(tt.token)
+			if ok != tt.found {
+				t.Fatalf("expected found=%v, got %v", tt.found, ok)
+			}
+			if ok && session.Identity() != tt.identity {
+				t.Errorf("expected identity %q, got %q", tt.identity, session.Identity())
+			}
+		})
+	}
+}
@@ -5,6 +5,9 @@ const (
 	StatusBadRequest          = 4000 // Некорректный формат
 	StatusNotFound            = 4004 // Неизвестная команда
 	StatusUnauthorized        = 4003 // Неавторизованное действие
+	StatusRequestTimeout      = 4008 // Истекло время ожидания запроса
 	StatusConflict            = 4009 // Комната уже существует
+	StatusPayloadTooLarge     = 4013 // Кадр превышает допустимый размер
+	StatusTooManyRequests     = 4029 // Превышен лимит запросов
 	StatusInternalServerError = 5000 // Ошибка на сервере
 )
@@ -0,0 +1,224 @@
+package tcp_server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Виды сообщений, которые сервер отправляет по WebSocket.
+const (
+	KindResponse = "response"
+	KindEvent    = "event"
+)
+
+// WSRequest — JSON-запрос клиента по WebSocket.
+type WSRequest struct {
+	ID      string          `json:"id"`
+	Command string          `json:"command"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// WSMessage — JSON-ответ на запрос или событие, отправленное сервером.
+type WSMessage struct {
+	ID         string          `json:"id,omitempty"`
+	Kind       string          `json:"kind"`
+	StatusCode int32           `json:"status_code"`
+	Message    string          `json:"message"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+}
+
+// WSGateway принимает WebSocket-подключения и обрабатывает их теми же обработчиками, что и TCP-сервер.
+// Ответы и события передаются по одному соединению.
+// Текстовые кадры содержат JSON (WSRequest/WSMessage), бинарные — Protobuf (ClientMessage/ServerResponse).
+type WSGateway struct {
+	address        string
+	server         *Server
+	upgrader       websocket.Upgrader
+	allowedOrigins map[string]struct{}
+	logger         ILogger
+}
+
+// NewWSGateway создаёт шлюз, использующий обработчики и сессии сервера.
+// allowedOrigins — origin веб-клиентов (например, https://hangman.example), которым разрешено подключаться
+// со страниц другого хоста; пустые строки пропускаются.
+func NewWSGateway(address string, server *Server, allowedOrigins []string) *WSGateway {
+	g := &WSGateway{
+		address:        address,
+		server:         server,
+		allowedOrigins: make(map[string]struct{}, len(allowedOrigins)),
+		logger:         server.logger,
+	}
+	for _, origin := range allowedOrigins {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			g.allowedOrigins[strings.ToLower(strings.TrimSuffix(origin, "/"))] = struct{}{}
+		}
+	}
+	g.upgrader.CheckOrigin = g.checkOrigin
+	return g
+}
+
+// checkOrigin пускает клиентов без Origin (не браузеры), страницы того же хоста и origin из списка разрешённых.
+// Иначе любая страница могла бы открыть соединение от имени посетителя.
+func (g *WSGateway) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if _, ok := g.allowedOrigins[strings.ToLower(origin)]; ok {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// Start запускает HTTP-сервер, принимающий WebSocket-подключения на /ws.
+func (g *WSGateway) Start() error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", g.handleWebSocket)
+
+	g.logger.Info(fmt.Sprintf("WebSocket gateway is listening on: %s", g.address))
+	return http.ListenAndServe(g.address, mux)
+}
+
+// wsSubscriber отправляет ответы и события в WebSocket-соединение.
+type wsSubscriber struct {
+	conn         *websocket.Conn
+	binary       bool // Клиент общается Protobuf-кадрами
+	writeTimeout time.Duration
+	mu           sync.Mutex
+}
+
+func (w *wsSubscriber) Send(resp *ServerResponse) error {
+	return w.write("", KindEvent, resp)
+}
+
+func (w *wsSubscriber) Close() error {
+	return w.conn.Close()
+}
+
+func (w *wsSubscriber) String() string {
+	return w.conn.RemoteAddr().String()
+}
+
+func (w *wsSubscriber) setBinary(binary bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.binary = binary
+}
+
+// write сериализует ответ в формате, которым пользуется клиент.
+func (w *wsSubscriber) write(id, kind string, resp *ServerResponse) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := setDeadline(w.conn.SetWriteDeadline, w.writeTimeout); err != nil {
+		return err
+	}
+	if w.binary {
+		respBytes, err := proto.Marshal(resp)
+		if err != nil {
+			return err
+		}
+		return w.conn.WriteMessage(websocket.BinaryMessage, respBytes)
+	}
+
+	message := WSMessage{
+		ID:         id,
+		Kind:       kind,
+		StatusCode: resp.StatusCode,
+		Message:    resp.Message,
+	}
+	if json.Valid(resp.Payload) {
+		message.Payload = resp.Payload
+	}
+	return w.conn.WriteJSON(message)
+}
+
+func (g *WSGateway) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := g.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		g.logger.Error(fmt.Sprintf("Failed to upgrade connection: %v", err))
+		return
+	}
+	clientAddr := conn.RemoteAddr().String()
+	subscriber := &wsSubscriber{conn: conn, writeTimeout: g.server.writeTimeout}
+	// При превышении лимита gorilla/websocket закрывает соединение с кодом 1009
+	conn.SetReadLimit(int64(g.server.maxFrameSize))
+	defer func() {
+		conn.Close()
+		g.logger.Info(fmt.Sprintf("WebSocket client disconnected: %s", clientAddr))
+	}()
+	g.logger.Info(fmt.Sprintf("New WebSocket connection from %s", clientAddr))
+
+	session, err := g.server.sessions.Issue()
+	if err != nil {
+		g.logger.Error(fmt.Sprintf("Failed to issue session for %s: %v", clientAddr, err))
+		return
+	}
+	// События сессии отправляются в это же соединение
+	g.server.notificationServer.Subscribe(session, subscriber)
+	defer func() {
+		g.server.sessions.Revoke(session.Token())
+		g.server.notificationServer.Disconnect(session)
+	}()
+
+	ctx := context.Background()
+	ctx = SetConn(ctx, conn.NetConn())
+	ctx = SetLogger(ctx, g.logger)
+	ctx = SetNotificationServer(ctx, g.server.notificationServer)
+	ctx = SetSession(ctx, session)
+	g.server.ctxRepo.UpdateOrInsertCtx(clientAddr, ctx)
+	defer g.server.ctxRepo.CancelContext(clientAddr)
+	if connCtx, ok := g.server.ctxRepo.GetContext(clientAddr); ok {
+		ctx = *connCtx
+	}
+
+	for {
+		if err := setDeadline(conn.SetReadDeadline, g.server.idleTimeout); err != nil {
+			return
+		}
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			g.logger.Error(fmt.Sprintf("Failed to read WebSocket message: %v", err))
+			return
+		}
+		subscriber.setBinary(messageType == websocket.BinaryMessage)
+
+		id, resp := g.process(ctx, messageType, data)
+		if err := subscriber.write(id, KindResponse, resp); err != nil {
+			g.logger.Error(fmt.Sprintf("Failed to write WebSocket message: %v", err))
+			return
+		}
+	}
+}
+
+// process разбирает кадр клиента и передаёт команду обработчику сервера.
+func (g *WSGateway) process(ctx context.Context, messageType int, data []byte) (string, *ServerResponse) {
+	if messageType == websocket.BinaryMessage {
+		var clientMsg ClientMessage
+		if err := proto.Unmarshal(data, &clientMsg); err != nil {
+			return "", &ServerResponse{StatusCode: StatusBadRequest, Message: "Invalid Protobuf format"}
+		}
+		resp := g.server.dispatch(ctx, clientMsg.Command, clientMsg.Payload)
+		resp.RequestId = clientMsg.RequestId
+		return clientMsg.RequestId, resp
+	}
+
+	var req WSRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return "", &ServerResponse{StatusCode: StatusBadRequest, Message: "Invalid JSON format"}
+	}
+	payload := []byte(req.Payload)
+	if len(payload) == 0 {
+		payload = []byte("{}")
+	}
+	return req.ID, g.server.dispatch(ctx, req.Command, payload)
+}
@@ -0,0 +1,136 @@
+package tcp_server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+)
+
+// newTestGateway запускает шлюз поверх тестового сервера и возвращает адрес ws://
+func newTestGateway(t *testing.T, allowedOrigins ...string) (*Server, string) {
+	t.Helper()
+	s, _ := newTestServer(t)
+	gateway := NewWSGateway("", s, allowedOrigins)
+	httpServer := httptest.NewServer(http.HandlerFunc(gateway.handleWebSocket))
+	t.Cleanup(httpServer.Close)
+	return s, "ws" + strings.TrimPrefix(httpServer.URL, "http")
+}
+
+func dialWS(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	return conn
+}
+
+func TestWSGatewayJSON(t *testing.T) {
+	s, url := newTestGateway(t)
+	conn := dialWS(t, url)
+
+	if err := conn.WriteJSON(WSRequest{ID: "1", Command: "LOGIN", Payload: json.RawMessage(`{"username":"alice"}`)}); err != nil {
+		t.Fatal(err)
+	}
+	var resp WSMessage
+	if err := conn.ReadJSON(&resp); err != nil {
+		t.Fatal(err)
+	}
+	var login testLogin
+	json.Unmarshal(resp.Payload, &login)
+	if resp.ID != "1" || resp.Kind != KindResponse || resp.StatusCode != StatusSuccess || login.SessionToken == "" {
+		t.Fatalf("unexpected LOGIN response: %+v", resp)
+	}
+	if session, ok := s.sessions.Resolve(login.SessionToken); !ok || session.Identity() != "alice" {
+		t.Fatalf("gateway must issue a session bound on LOGIN")
+	}
+
+	// События приходят в то же соединение без SUBSCRIBE
+	s.notificationServer.Notify("GameStarted", []byte(`{"round":1}`), []string{"alice"})
+	var event WSMessage
+	if err := conn.ReadJSON(&event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Kind != KindEvent || event.Message != "GameStarted" || event.ID != "" || string(event.Payload) != `{"round":1}` {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+
+	if err := conn.WriteMessage(websocket.TextMessage, []byte("{")); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.ReadJSON(&resp); err != nil || resp.StatusCode != StatusBadRequest {
+		t.Fatalf("expected 4000 for invalid JSON, got %+v, %v", resp, err)
+	}
+}
+
+func TestWSGatewayProtobuf(t *testing.T) {
+	s, url := newTestGateway(t)
+	conn := dialWS(t, url)
+
+	message, _ := proto.Marshal(&ClientMessage{Command: "LOGIN", Payload: []byte(`{"username":"bob"}`), RequestId: "b1"})
+	if err := conn.WriteMessage(websocket.BinaryMessage, message); err != nil {
+		t.Fatal(err)
+	}
+	messageType, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp ServerResponse
+	if messageType != websocket.BinaryMessage || proto.Unmarshal(data, &resp) != nil {
+		t.Fatalf("binary request must get a Protobuf response, got type %d", messageType)
+	}
+	if resp.RequestId != "b1" || resp.StatusCode != StatusSuccess {
+		t.Fatalf("unexpected response: %v", &resp)
+	}
+
+	// После бинарного запроса события тоже приходят Protobuf-кадрами
+	s.notificationServer.Notify("TurnChanged", []byte(`{}`), []string{"bob"})
+	if messageType, data, err = conn.ReadMessage(); err != nil || messageType != websocket.BinaryMessage {
+		t.Fatalf("expected a binary event, got type %d, %v", messageType, err)
+	}
+	var event ServerResponse
+	if err := proto.Unmarshal(data, &event); err != nil || event.Kind != MessageKind_EVENT || event.Message != "TurnChanged" {
+		t.Fatalf("unexpected event: %v, %v", &event, err)
+	}
+}
+
+func TestWSGatewayOrigin(t *testing.T) {
+	_, url := newTestGateway(t, "https://hangman.example")
+	tests := []struct {
+		name    string
+		origin  string
+		allowed bool
+	}{
+		{name: "no origin", allowed: true},
+		{name: "allowed origin", origin: "https://hangman.example", allowed: true},
+		{name: "same host", origin: strings.Replace(url, "ws://", "http://", 1), allowed: true},
+		{name: "foreign page", origin: "https://evil.example"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.origin != "" {
+				header.Set("Origin", tt.origin)
+			}
+			conn, resp, err := websocket.DefaultDialer.Dial(url, header)
+			if tt.allowed {
+				if err != nil {
+					t.Fatalf("expected to connect, got %v", err)
+				}
+				conn.Close()
+				return
+			}
+			if err == nil || resp == nil || resp.StatusCode != http.StatusForbidden {
+				t.Fatalf("expected 403 for origin %s, got %v", tt.origin, err)
+			}
+		})
+	}
+}